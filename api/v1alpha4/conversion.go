@@ -51,6 +51,38 @@ func (src *ClientMount) ConvertTo(dstRaw conversion.Hub) error {
 	// hub-specific then copy it into 'dst' from 'restored'.
 	// Otherwise, you may comment out UnmarshalData() until it's needed.
 
+	dst.Spec.UnmountPolicy = restored.Spec.UnmountPolicy
+	dst.Status.Conditions = restored.Status.Conditions
+
+	for i := range dst.Spec.Mounts {
+		if i >= len(restored.Spec.Mounts) {
+			break
+		}
+
+		mount := &dst.Spec.Mounts[i]
+		restoredMount := restored.Spec.Mounts[i]
+		if mount.MountPath == restoredMount.MountPath && mount.Device.Type == restoredMount.Device.Type {
+			mount.Device.NFS = restoredMount.Device.NFS
+			mount.Device.Generic = restoredMount.Device.Generic
+			mount.Device.Bind = restoredMount.Device.Bind
+		}
+	}
+
+	for i := range dst.Status.Mounts {
+		if i >= len(restored.Status.Mounts) {
+			break
+		}
+
+		mount := &dst.Status.Mounts[i]
+		restoredMount := restored.Status.Mounts[i]
+		if mount.State == restoredMount.State && mount.Ready == restoredMount.Ready {
+			mount.Busy = restoredMount.Busy
+			mount.Message = restoredMount.Message
+			mount.Error = restoredMount.Error
+			mount.LastTransitionTime = restoredMount.LastTransitionTime
+		}
+	}
+
 	return nil
 }
 
@@ -291,6 +323,10 @@ func (src *SystemConfiguration) ConvertTo(dstRaw conversion.Hub) error {
 	// hub-specific then copy it into 'dst' from 'restored'.
 	// Otherwise, you may comment out UnmarshalData() until it's needed.
 
+	dst.Spec.WorkflowStateTimeouts = restored.Spec.WorkflowStateTimeouts
+	dst.Spec.WorkflowMaxStateRetries = restored.Spec.WorkflowMaxStateRetries
+	dst.Status.Conditions = restored.Status.Conditions
+
 	return nil
 }
 
@@ -368,6 +404,33 @@ func (src *Workflow) ConvertTo(dstRaw conversion.Hub) error {
 
 	if hasAnno {
 		dst.Spec.ForceReady = restored.Spec.ForceReady
+		dst.Spec.StateTimeouts = restored.Spec.StateTimeouts
+		dst.Spec.Cancel = restored.Spec.Cancel
+		dst.Spec.Suspend = restored.Spec.Suspend
+		dst.Spec.RetryCount = restored.Spec.RetryCount
+		dst.Spec.DirectiveRuleSelector = restored.Spec.DirectiveRuleSelector
+		dst.Status.Conditions = restored.Status.Conditions
+		dst.Status.History = restored.Status.History
+		dst.Status.Cancelled = restored.Status.Cancelled
+		dst.Status.Suspended = restored.Status.Suspended
+		dst.Status.StateTimeoutStart = restored.Status.StateTimeoutStart
+		dst.Status.RetryCount = restored.Status.RetryCount
+		dst.Status.StateRetryCount = restored.Status.StateRetryCount
+		dst.Status.DirectiveDefaults = restored.Status.DirectiveDefaults
+		dst.Status.DirectiveRules = restored.Status.DirectiveRules
+
+		for i := range dst.Status.Drivers {
+			if i >= len(restored.Status.Drivers) {
+				break
+			}
+
+			driver := &dst.Status.Drivers[i]
+			restoredDriver := restored.Status.Drivers[i]
+			if driver.DriverID == restoredDriver.DriverID && driver.DWDIndex == restoredDriver.DWDIndex && driver.WatchState == restoredDriver.WatchState {
+				driver.DependsOn = restoredDriver.DependsOn
+				driver.Blocked = restoredDriver.Blocked
+			}
+		}
 	}
 
 	return nil
@@ -477,14 +540,46 @@ func (dst *WorkflowList) ConvertFrom(srcRaw conversion.Hub) error {
 // The conversion-gen tool dropped these from zz_generated.conversion.go to
 // force us to acknowledge that we are addressing the conversion requirements.
 
+func Convert_v1alpha7_ClientMountDevice_To_v1alpha4_ClientMountDevice(in *dwsv1alpha7.ClientMountDevice, out *ClientMountDevice, s apiconversion.Scope) error {
+	return autoConvert_v1alpha7_ClientMountDevice_To_v1alpha4_ClientMountDevice(in, out, s)
+}
+
+func Convert_v1alpha7_ClientMountInfoStatus_To_v1alpha4_ClientMountInfoStatus(in *dwsv1alpha7.ClientMountInfoStatus, out *ClientMountInfoStatus, s apiconversion.Scope) error {
+	return autoConvert_v1alpha7_ClientMountInfoStatus_To_v1alpha4_ClientMountInfoStatus(in, out, s)
+}
+
+func Convert_v1alpha7_ClientMountSpec_To_v1alpha4_ClientMountSpec(in *dwsv1alpha7.ClientMountSpec, out *ClientMountSpec, s apiconversion.Scope) error {
+	return autoConvert_v1alpha7_ClientMountSpec_To_v1alpha4_ClientMountSpec(in, out, s)
+}
+
+func Convert_v1alpha7_ClientMountStatus_To_v1alpha4_ClientMountStatus(in *dwsv1alpha7.ClientMountStatus, out *ClientMountStatus, s apiconversion.Scope) error {
+	return autoConvert_v1alpha7_ClientMountStatus_To_v1alpha4_ClientMountStatus(in, out, s)
+}
+
 func Convert_v1alpha7_ServersStatusStorage_To_v1alpha4_ServersStatusStorage(in *dwsv1alpha7.ServersStatusStorage, out *ServersStatusStorage, s apiconversion.Scope) error {
 	return autoConvert_v1alpha7_ServersStatusStorage_To_v1alpha4_ServersStatusStorage(in, out, s)
 }
 
+func Convert_v1alpha7_SystemConfigurationSpec_To_v1alpha4_SystemConfigurationSpec(in *dwsv1alpha7.SystemConfigurationSpec, out *SystemConfigurationSpec, s apiconversion.Scope) error {
+	return autoConvert_v1alpha7_SystemConfigurationSpec_To_v1alpha4_SystemConfigurationSpec(in, out, s)
+}
+
+func Convert_v1alpha7_SystemConfigurationStatus_To_v1alpha4_SystemConfigurationStatus(in *dwsv1alpha7.SystemConfigurationStatus, out *SystemConfigurationStatus, s apiconversion.Scope) error {
+	return autoConvert_v1alpha7_SystemConfigurationStatus_To_v1alpha4_SystemConfigurationStatus(in, out, s)
+}
+
+func Convert_v1alpha7_WorkflowDriverStatus_To_v1alpha4_WorkflowDriverStatus(in *dwsv1alpha7.WorkflowDriverStatus, out *WorkflowDriverStatus, s apiconversion.Scope) error {
+	return autoConvert_v1alpha7_WorkflowDriverStatus_To_v1alpha4_WorkflowDriverStatus(in, out, s)
+}
+
 func Convert_v1alpha7_WorkflowSpec_To_v1alpha4_WorkflowSpec(in *dwsv1alpha7.WorkflowSpec, out *WorkflowSpec, s apiconversion.Scope) error {
 	return autoConvert_v1alpha7_WorkflowSpec_To_v1alpha4_WorkflowSpec(in, out, s)
 }
 
+func Convert_v1alpha7_WorkflowStatus_To_v1alpha4_WorkflowStatus(in *dwsv1alpha7.WorkflowStatus, out *WorkflowStatus, s apiconversion.Scope) error {
+	return autoConvert_v1alpha7_WorkflowStatus_To_v1alpha4_WorkflowStatus(in, out, s)
+}
+
 // convertResourceStatusFromHub converts a v1alpha7 ResourceStatus to v1alpha4.
 // FencedStatus is mapped to OfflineStatus since Fenced doesn't exist in older API versions.
 func convertResourceStatusFromHub(in dwsv1alpha7.ResourceStatus) ResourceStatus {
@@ -78,11 +78,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha7.ClientMountDevice)(nil), (*ClientMountDevice)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha7_ClientMountDevice_To_v1alpha5_ClientMountDevice(a.(*v1alpha7.ClientMountDevice), b.(*ClientMountDevice), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*ClientMountDeviceLVM)(nil), (*v1alpha7.ClientMountDeviceLVM)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha5_ClientMountDeviceLVM_To_v1alpha7_ClientMountDeviceLVM(a.(*ClientMountDeviceLVM), b.(*v1alpha7.ClientMountDeviceLVM), scope)
 	}); err != nil {
@@ -128,11 +123,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha7.ClientMountInfoStatus)(nil), (*ClientMountInfoStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha7_ClientMountInfoStatus_To_v1alpha5_ClientMountInfoStatus(a.(*v1alpha7.ClientMountInfoStatus), b.(*ClientMountInfoStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*ClientMountList)(nil), (*v1alpha7.ClientMountList)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha5_ClientMountList_To_v1alpha7_ClientMountList(a.(*ClientMountList), b.(*v1alpha7.ClientMountList), scope)
 	}); err != nil {
@@ -158,21 +148,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha7.ClientMountSpec)(nil), (*ClientMountSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha7_ClientMountSpec_To_v1alpha5_ClientMountSpec(a.(*v1alpha7.ClientMountSpec), b.(*ClientMountSpec), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*ClientMountStatus)(nil), (*v1alpha7.ClientMountStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha5_ClientMountStatus_To_v1alpha7_ClientMountStatus(a.(*ClientMountStatus), b.(*v1alpha7.ClientMountStatus), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha7.ClientMountStatus)(nil), (*ClientMountStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha7_ClientMountStatus_To_v1alpha5_ClientMountStatus(a.(*v1alpha7.ClientMountStatus), b.(*ClientMountStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*ComputeBreakdown)(nil), (*v1alpha7.ComputeBreakdown)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha5_ComputeBreakdown_To_v1alpha7_ComputeBreakdown(a.(*ComputeBreakdown), b.(*v1alpha7.ComputeBreakdown), scope)
 	}); err != nil {
@@ -563,21 +543,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha7.SystemConfigurationSpec)(nil), (*SystemConfigurationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha7_SystemConfigurationSpec_To_v1alpha5_SystemConfigurationSpec(a.(*v1alpha7.SystemConfigurationSpec), b.(*SystemConfigurationSpec), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*SystemConfigurationStatus)(nil), (*v1alpha7.SystemConfigurationStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha5_SystemConfigurationStatus_To_v1alpha7_SystemConfigurationStatus(a.(*SystemConfigurationStatus), b.(*v1alpha7.SystemConfigurationStatus), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha7.SystemConfigurationStatus)(nil), (*SystemConfigurationStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha7_SystemConfigurationStatus_To_v1alpha5_SystemConfigurationStatus(a.(*v1alpha7.SystemConfigurationStatus), b.(*SystemConfigurationStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*SystemConfigurationStorageNode)(nil), (*v1alpha7.SystemConfigurationStorageNode)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha5_SystemConfigurationStorageNode_To_v1alpha7_SystemConfigurationStorageNode(a.(*SystemConfigurationStorageNode), b.(*v1alpha7.SystemConfigurationStorageNode), scope)
 	}); err != nil {
@@ -633,11 +603,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha7.WorkflowDriverStatus)(nil), (*WorkflowDriverStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha7_WorkflowDriverStatus_To_v1alpha5_WorkflowDriverStatus(a.(*v1alpha7.WorkflowDriverStatus), b.(*WorkflowDriverStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*WorkflowList)(nil), (*v1alpha7.WorkflowList)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha5_WorkflowList_To_v1alpha7_WorkflowList(a.(*WorkflowList), b.(*v1alpha7.WorkflowList), scope)
 	}); err != nil {
@@ -653,21 +618,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha7.WorkflowSpec)(nil), (*WorkflowSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha7_WorkflowSpec_To_v1alpha5_WorkflowSpec(a.(*v1alpha7.WorkflowSpec), b.(*WorkflowSpec), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*WorkflowStatus)(nil), (*v1alpha7.WorkflowStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha5_WorkflowStatus_To_v1alpha7_WorkflowStatus(a.(*WorkflowStatus), b.(*v1alpha7.WorkflowStatus), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha7.WorkflowStatus)(nil), (*WorkflowStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha7_WorkflowStatus_To_v1alpha5_WorkflowStatus(a.(*v1alpha7.WorkflowStatus), b.(*WorkflowStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*WorkflowTokenSecret)(nil), (*v1alpha7.WorkflowTokenSecret)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha5_WorkflowTokenSecret_To_v1alpha7_WorkflowTokenSecret(a.(*WorkflowTokenSecret), b.(*v1alpha7.WorkflowTokenSecret), scope)
 	}); err != nil {
@@ -678,6 +633,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1alpha7.ClientMountDevice)(nil), (*ClientMountDevice)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha7_ClientMountDevice_To_v1alpha5_ClientMountDevice(a.(*v1alpha7.ClientMountDevice), b.(*ClientMountDevice), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha7.ClientMountInfoStatus)(nil), (*ClientMountInfoStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha7_ClientMountInfoStatus_To_v1alpha5_ClientMountInfoStatus(a.(*v1alpha7.ClientMountInfoStatus), b.(*ClientMountInfoStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha7.ClientMountSpec)(nil), (*ClientMountSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha7_ClientMountSpec_To_v1alpha5_ClientMountSpec(a.(*v1alpha7.ClientMountSpec), b.(*ClientMountSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha7.ClientMountStatus)(nil), (*ClientMountStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha7_ClientMountStatus_To_v1alpha5_ClientMountStatus(a.(*v1alpha7.ClientMountStatus), b.(*ClientMountStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1alpha7.Node)(nil), (*Node)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha7_Node_To_v1alpha5_Node(a.(*v1alpha7.Node), b.(*Node), scope)
 	}); err != nil {
@@ -693,6 +668,31 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1alpha7.SystemConfigurationSpec)(nil), (*SystemConfigurationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha7_SystemConfigurationSpec_To_v1alpha5_SystemConfigurationSpec(a.(*v1alpha7.SystemConfigurationSpec), b.(*SystemConfigurationSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha7.SystemConfigurationStatus)(nil), (*SystemConfigurationStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha7_SystemConfigurationStatus_To_v1alpha5_SystemConfigurationStatus(a.(*v1alpha7.SystemConfigurationStatus), b.(*SystemConfigurationStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha7.WorkflowDriverStatus)(nil), (*WorkflowDriverStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha7_WorkflowDriverStatus_To_v1alpha5_WorkflowDriverStatus(a.(*v1alpha7.WorkflowDriverStatus), b.(*WorkflowDriverStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha7.WorkflowSpec)(nil), (*WorkflowSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha7_WorkflowSpec_To_v1alpha5_WorkflowSpec(a.(*v1alpha7.WorkflowSpec), b.(*WorkflowSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha7.WorkflowStatus)(nil), (*WorkflowStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha7_WorkflowStatus_To_v1alpha5_WorkflowStatus(a.(*v1alpha7.WorkflowStatus), b.(*WorkflowStatus), scope)
+	}); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -794,14 +794,12 @@ func autoConvert_v1alpha7_ClientMountDevice_To_v1alpha5_ClientMountDevice(in *v1
 	out.Lustre = (*ClientMountDeviceLustre)(unsafe.Pointer(in.Lustre))
 	out.LVM = (*ClientMountDeviceLVM)(unsafe.Pointer(in.LVM))
 	out.DeviceReference = (*ClientMountDeviceReference)(unsafe.Pointer(in.DeviceReference))
+	// WARNING: in.NFS requires manual conversion: does not exist in peer-type
+	// WARNING: in.Generic requires manual conversion: does not exist in peer-type
+	// WARNING: in.Bind requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha7_ClientMountDevice_To_v1alpha5_ClientMountDevice is an autogenerated conversion function.
-func Convert_v1alpha7_ClientMountDevice_To_v1alpha5_ClientMountDevice(in *v1alpha7.ClientMountDevice, out *ClientMountDevice, s conversion.Scope) error {
-	return autoConvert_v1alpha7_ClientMountDevice_To_v1alpha5_ClientMountDevice(in, out, s)
-}
-
 func autoConvert_v1alpha5_ClientMountDeviceLVM_To_v1alpha7_ClientMountDeviceLVM(in *ClientMountDeviceLVM, out *v1alpha7.ClientMountDeviceLVM, s conversion.Scope) error {
 	out.DeviceType = v1alpha7.ClientMountLVMDeviceType(in.DeviceType)
 	out.NVMeInfo = *(*[]v1alpha7.ClientMountNVMeDesc)(unsafe.Pointer(&in.NVMeInfo))
@@ -926,17 +924,26 @@ func Convert_v1alpha5_ClientMountInfoStatus_To_v1alpha7_ClientMountInfoStatus(in
 func autoConvert_v1alpha7_ClientMountInfoStatus_To_v1alpha5_ClientMountInfoStatus(in *v1alpha7.ClientMountInfoStatus, out *ClientMountInfoStatus, s conversion.Scope) error {
 	out.State = ClientMountState(in.State)
 	out.Ready = in.Ready
+	// WARNING: in.Message requires manual conversion: does not exist in peer-type
+	// WARNING: in.Busy requires manual conversion: does not exist in peer-type
+	// WARNING: in.Error requires manual conversion: does not exist in peer-type
+	// WARNING: in.LastTransitionTime requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha7_ClientMountInfoStatus_To_v1alpha5_ClientMountInfoStatus is an autogenerated conversion function.
-func Convert_v1alpha7_ClientMountInfoStatus_To_v1alpha5_ClientMountInfoStatus(in *v1alpha7.ClientMountInfoStatus, out *ClientMountInfoStatus, s conversion.Scope) error {
-	return autoConvert_v1alpha7_ClientMountInfoStatus_To_v1alpha5_ClientMountInfoStatus(in, out, s)
-}
-
 func autoConvert_v1alpha5_ClientMountList_To_v1alpha7_ClientMountList(in *ClientMountList, out *v1alpha7.ClientMountList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]v1alpha7.ClientMount)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]v1alpha7.ClientMount, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha5_ClientMount_To_v1alpha7_ClientMount(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -947,7 +954,17 @@ func Convert_v1alpha5_ClientMountList_To_v1alpha7_ClientMountList(in *ClientMoun
 
 func autoConvert_v1alpha7_ClientMountList_To_v1alpha5_ClientMountList(in *v1alpha7.ClientMountList, out *ClientMountList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]ClientMount)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClientMount, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha7_ClientMount_To_v1alpha5_ClientMount(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -983,7 +1000,17 @@ func Convert_v1alpha7_ClientMountNVMeDesc_To_v1alpha5_ClientMountNVMeDesc(in *v1
 func autoConvert_v1alpha5_ClientMountSpec_To_v1alpha7_ClientMountSpec(in *ClientMountSpec, out *v1alpha7.ClientMountSpec, s conversion.Scope) error {
 	out.Node = in.Node
 	out.DesiredState = v1alpha7.ClientMountState(in.DesiredState)
-	out.Mounts = *(*[]v1alpha7.ClientMountInfo)(unsafe.Pointer(&in.Mounts))
+	if in.Mounts != nil {
+		in, out := &in.Mounts, &out.Mounts
+		*out = make([]v1alpha7.ClientMountInfo, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha5_ClientMountInfo_To_v1alpha7_ClientMountInfo(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Mounts = nil
+	}
 	return nil
 }
 
@@ -995,17 +1022,33 @@ func Convert_v1alpha5_ClientMountSpec_To_v1alpha7_ClientMountSpec(in *ClientMoun
 func autoConvert_v1alpha7_ClientMountSpec_To_v1alpha5_ClientMountSpec(in *v1alpha7.ClientMountSpec, out *ClientMountSpec, s conversion.Scope) error {
 	out.Node = in.Node
 	out.DesiredState = ClientMountState(in.DesiredState)
-	out.Mounts = *(*[]ClientMountInfo)(unsafe.Pointer(&in.Mounts))
+	if in.Mounts != nil {
+		in, out := &in.Mounts, &out.Mounts
+		*out = make([]ClientMountInfo, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha7_ClientMountInfo_To_v1alpha5_ClientMountInfo(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Mounts = nil
+	}
+	// WARNING: in.UnmountPolicy requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha7_ClientMountSpec_To_v1alpha5_ClientMountSpec is an autogenerated conversion function.
-func Convert_v1alpha7_ClientMountSpec_To_v1alpha5_ClientMountSpec(in *v1alpha7.ClientMountSpec, out *ClientMountSpec, s conversion.Scope) error {
-	return autoConvert_v1alpha7_ClientMountSpec_To_v1alpha5_ClientMountSpec(in, out, s)
-}
-
 func autoConvert_v1alpha5_ClientMountStatus_To_v1alpha7_ClientMountStatus(in *ClientMountStatus, out *v1alpha7.ClientMountStatus, s conversion.Scope) error {
-	out.Mounts = *(*[]v1alpha7.ClientMountInfoStatus)(unsafe.Pointer(&in.Mounts))
+	if in.Mounts != nil {
+		in, out := &in.Mounts, &out.Mounts
+		*out = make([]v1alpha7.ClientMountInfoStatus, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha5_ClientMountInfoStatus_To_v1alpha7_ClientMountInfoStatus(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Mounts = nil
+	}
 	out.AllReady = in.AllReady
 	if err := Convert_v1alpha5_ResourceError_To_v1alpha7_ResourceError(&in.ResourceError, &out.ResourceError, s); err != nil {
 		return err
@@ -1019,19 +1062,25 @@ func Convert_v1alpha5_ClientMountStatus_To_v1alpha7_ClientMountStatus(in *Client
 }
 
 func autoConvert_v1alpha7_ClientMountStatus_To_v1alpha5_ClientMountStatus(in *v1alpha7.ClientMountStatus, out *ClientMountStatus, s conversion.Scope) error {
-	out.Mounts = *(*[]ClientMountInfoStatus)(unsafe.Pointer(&in.Mounts))
+	if in.Mounts != nil {
+		in, out := &in.Mounts, &out.Mounts
+		*out = make([]ClientMountInfoStatus, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha7_ClientMountInfoStatus_To_v1alpha5_ClientMountInfoStatus(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Mounts = nil
+	}
 	out.AllReady = in.AllReady
 	if err := Convert_v1alpha7_ResourceError_To_v1alpha5_ResourceError(&in.ResourceError, &out.ResourceError, s); err != nil {
 		return err
 	}
+	// WARNING: in.Conditions requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha7_ClientMountStatus_To_v1alpha5_ClientMountStatus is an autogenerated conversion function.
-func Convert_v1alpha7_ClientMountStatus_To_v1alpha5_ClientMountStatus(in *v1alpha7.ClientMountStatus, out *ClientMountStatus, s conversion.Scope) error {
-	return autoConvert_v1alpha7_ClientMountStatus_To_v1alpha5_ClientMountStatus(in, out, s)
-}
-
 func autoConvert_v1alpha5_ComputeBreakdown_To_v1alpha7_ComputeBreakdown(in *ComputeBreakdown, out *v1alpha7.ComputeBreakdown, s conversion.Scope) error {
 	if err := Convert_v1alpha5_ComputeConstraints_To_v1alpha7_ComputeConstraints(&in.Constraints, &out.Constraints, s); err != nil {
 		return err
@@ -2073,7 +2122,17 @@ func Convert_v1alpha7_SystemConfigurationExternalComputeNode_To_v1alpha5_SystemC
 
 func autoConvert_v1alpha5_SystemConfigurationList_To_v1alpha7_SystemConfigurationList(in *SystemConfigurationList, out *v1alpha7.SystemConfigurationList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]v1alpha7.SystemConfiguration)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]v1alpha7.SystemConfiguration, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha5_SystemConfiguration_To_v1alpha7_SystemConfiguration(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -2084,7 +2143,17 @@ func Convert_v1alpha5_SystemConfigurationList_To_v1alpha7_SystemConfigurationLis
 
 func autoConvert_v1alpha7_SystemConfigurationList_To_v1alpha5_SystemConfigurationList(in *v1alpha7.SystemConfigurationList, out *SystemConfigurationList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]SystemConfiguration)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SystemConfiguration, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha7_SystemConfiguration_To_v1alpha5_SystemConfiguration(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -2111,14 +2180,11 @@ func autoConvert_v1alpha7_SystemConfigurationSpec_To_v1alpha5_SystemConfiguratio
 	out.StorageNodes = *(*[]SystemConfigurationStorageNode)(unsafe.Pointer(&in.StorageNodes))
 	out.Ports = *(*[]intstr.IntOrString)(unsafe.Pointer(&in.Ports))
 	out.PortsCooldownInSeconds = in.PortsCooldownInSeconds
+	// WARNING: in.WorkflowStateTimeouts requires manual conversion: does not exist in peer-type
+	// WARNING: in.WorkflowMaxStateRetries requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha7_SystemConfigurationSpec_To_v1alpha5_SystemConfigurationSpec is an autogenerated conversion function.
-func Convert_v1alpha7_SystemConfigurationSpec_To_v1alpha5_SystemConfigurationSpec(in *v1alpha7.SystemConfigurationSpec, out *SystemConfigurationSpec, s conversion.Scope) error {
-	return autoConvert_v1alpha7_SystemConfigurationSpec_To_v1alpha5_SystemConfigurationSpec(in, out, s)
-}
-
 func autoConvert_v1alpha5_SystemConfigurationStatus_To_v1alpha7_SystemConfigurationStatus(in *SystemConfigurationStatus, out *v1alpha7.SystemConfigurationStatus, s conversion.Scope) error {
 	out.Ready = in.Ready
 	if err := Convert_v1alpha5_ResourceError_To_v1alpha7_ResourceError(&in.ResourceError, &out.ResourceError, s); err != nil {
@@ -2137,14 +2203,10 @@ func autoConvert_v1alpha7_SystemConfigurationStatus_To_v1alpha5_SystemConfigurat
 	if err := Convert_v1alpha7_ResourceError_To_v1alpha5_ResourceError(&in.ResourceError, &out.ResourceError, s); err != nil {
 		return err
 	}
+	// WARNING: in.Conditions requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha7_SystemConfigurationStatus_To_v1alpha5_SystemConfigurationStatus is an autogenerated conversion function.
-func Convert_v1alpha7_SystemConfigurationStatus_To_v1alpha5_SystemConfigurationStatus(in *v1alpha7.SystemConfigurationStatus, out *SystemConfigurationStatus, s conversion.Scope) error {
-	return autoConvert_v1alpha7_SystemConfigurationStatus_To_v1alpha5_SystemConfigurationStatus(in, out, s)
-}
-
 func autoConvert_v1alpha5_SystemConfigurationStorageNode_To_v1alpha7_SystemConfigurationStorageNode(in *SystemConfigurationStorageNode, out *v1alpha7.SystemConfigurationStorageNode, s conversion.Scope) error {
 	out.Type = in.Type
 	out.Name = in.Name
@@ -2299,17 +2361,24 @@ func autoConvert_v1alpha7_WorkflowDriverStatus_To_v1alpha5_WorkflowDriverStatus(
 	out.Message = in.Message
 	out.Error = in.Error
 	out.CompleteTime = (*metav1.MicroTime)(unsafe.Pointer(in.CompleteTime))
+	// WARNING: in.DependsOn requires manual conversion: does not exist in peer-type
+	// WARNING: in.Blocked requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha7_WorkflowDriverStatus_To_v1alpha5_WorkflowDriverStatus is an autogenerated conversion function.
-func Convert_v1alpha7_WorkflowDriverStatus_To_v1alpha5_WorkflowDriverStatus(in *v1alpha7.WorkflowDriverStatus, out *WorkflowDriverStatus, s conversion.Scope) error {
-	return autoConvert_v1alpha7_WorkflowDriverStatus_To_v1alpha5_WorkflowDriverStatus(in, out, s)
-}
-
 func autoConvert_v1alpha5_WorkflowList_To_v1alpha7_WorkflowList(in *WorkflowList, out *v1alpha7.WorkflowList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]v1alpha7.Workflow)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]v1alpha7.Workflow, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha5_Workflow_To_v1alpha7_Workflow(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -2320,7 +2389,17 @@ func Convert_v1alpha5_WorkflowList_To_v1alpha7_WorkflowList(in *WorkflowList, ou
 
 func autoConvert_v1alpha7_WorkflowList_To_v1alpha5_WorkflowList(in *v1alpha7.WorkflowList, out *WorkflowList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]Workflow)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Workflow, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha7_Workflow_To_v1alpha5_Workflow(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -2355,21 +2434,31 @@ func autoConvert_v1alpha7_WorkflowSpec_To_v1alpha5_WorkflowSpec(in *v1alpha7.Wor
 	out.Hurry = in.Hurry
 	out.ForceReady = in.ForceReady
 	out.DWDirectives = *(*[]string)(unsafe.Pointer(&in.DWDirectives))
+	// WARNING: in.StateTimeouts requires manual conversion: does not exist in peer-type
+	// WARNING: in.Cancel requires manual conversion: does not exist in peer-type
+	// WARNING: in.Suspend requires manual conversion: does not exist in peer-type
+	// WARNING: in.DirectiveRuleSelector requires manual conversion: does not exist in peer-type
+	// WARNING: in.RetryCount requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha7_WorkflowSpec_To_v1alpha5_WorkflowSpec is an autogenerated conversion function.
-func Convert_v1alpha7_WorkflowSpec_To_v1alpha5_WorkflowSpec(in *v1alpha7.WorkflowSpec, out *WorkflowSpec, s conversion.Scope) error {
-	return autoConvert_v1alpha7_WorkflowSpec_To_v1alpha5_WorkflowSpec(in, out, s)
-}
-
 func autoConvert_v1alpha5_WorkflowStatus_To_v1alpha7_WorkflowStatus(in *WorkflowStatus, out *v1alpha7.WorkflowStatus, s conversion.Scope) error {
 	out.State = v1alpha7.WorkflowState(in.State)
 	out.Ready = in.Ready
 	out.Status = in.Status
 	out.Message = in.Message
 	out.Env = *(*map[string]string)(unsafe.Pointer(&in.Env))
-	out.Drivers = *(*[]v1alpha7.WorkflowDriverStatus)(unsafe.Pointer(&in.Drivers))
+	if in.Drivers != nil {
+		in, out := &in.Drivers, &out.Drivers
+		*out = make([]v1alpha7.WorkflowDriverStatus, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha5_WorkflowDriverStatus_To_v1alpha7_WorkflowDriverStatus(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Drivers = nil
+	}
 	out.DirectiveBreakdowns = *(*[]v1.ObjectReference)(unsafe.Pointer(&in.DirectiveBreakdowns))
 	out.Requires = *(*[]string)(unsafe.Pointer(&in.Requires))
 	out.WorkflowToken = (*v1alpha7.WorkflowTokenSecret)(unsafe.Pointer(in.WorkflowToken))
@@ -2391,7 +2480,17 @@ func autoConvert_v1alpha7_WorkflowStatus_To_v1alpha5_WorkflowStatus(in *v1alpha7
 	out.Status = in.Status
 	out.Message = in.Message
 	out.Env = *(*map[string]string)(unsafe.Pointer(&in.Env))
-	out.Drivers = *(*[]WorkflowDriverStatus)(unsafe.Pointer(&in.Drivers))
+	if in.Drivers != nil {
+		in, out := &in.Drivers, &out.Drivers
+		*out = make([]WorkflowDriverStatus, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha7_WorkflowDriverStatus_To_v1alpha5_WorkflowDriverStatus(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Drivers = nil
+	}
 	out.DirectiveBreakdowns = *(*[]v1.ObjectReference)(unsafe.Pointer(&in.DirectiveBreakdowns))
 	out.Requires = *(*[]string)(unsafe.Pointer(&in.Requires))
 	out.WorkflowToken = (*WorkflowTokenSecret)(unsafe.Pointer(in.WorkflowToken))
@@ -2399,14 +2498,18 @@ func autoConvert_v1alpha7_WorkflowStatus_To_v1alpha5_WorkflowStatus(in *v1alpha7
 	out.DesiredStateChange = (*metav1.MicroTime)(unsafe.Pointer(in.DesiredStateChange))
 	out.ReadyChange = (*metav1.MicroTime)(unsafe.Pointer(in.ReadyChange))
 	out.ElapsedTimeLastState = in.ElapsedTimeLastState
+	// WARNING: in.History requires manual conversion: does not exist in peer-type
+	// WARNING: in.Cancelled requires manual conversion: does not exist in peer-type
+	// WARNING: in.Suspended requires manual conversion: does not exist in peer-type
+	// WARNING: in.StateTimeoutStart requires manual conversion: does not exist in peer-type
+	// WARNING: in.RetryCount requires manual conversion: does not exist in peer-type
+	// WARNING: in.StateRetryCount requires manual conversion: does not exist in peer-type
+	// WARNING: in.DirectiveRules requires manual conversion: does not exist in peer-type
+	// WARNING: in.DirectiveDefaults requires manual conversion: does not exist in peer-type
+	// WARNING: in.Conditions requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha7_WorkflowStatus_To_v1alpha5_WorkflowStatus is an autogenerated conversion function.
-func Convert_v1alpha7_WorkflowStatus_To_v1alpha5_WorkflowStatus(in *v1alpha7.WorkflowStatus, out *WorkflowStatus, s conversion.Scope) error {
-	return autoConvert_v1alpha7_WorkflowStatus_To_v1alpha5_WorkflowStatus(in, out, s)
-}
-
 func autoConvert_v1alpha5_WorkflowTokenSecret_To_v1alpha7_WorkflowTokenSecret(in *WorkflowTokenSecret, out *v1alpha7.WorkflowTokenSecret, s conversion.Scope) error {
 	out.SecretName = in.SecretName
 	out.SecretNamespace = in.SecretNamespace
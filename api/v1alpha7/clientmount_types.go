@@ -55,8 +55,8 @@ const (
 	ClientMountLVMDeviceTypeNVMe ClientMountLVMDeviceType = "nvme"
 )
 
-// ClientMountDeviceLVM defines an LVM device by the VG/LV pair and optionally
-// the drives that are the PVs.
+// ClientMountDeviceLVM defines an LVM device by the VG/LV pair and the drives
+// that are the PVs.
 type ClientMountDeviceLVM struct {
 	// Type of underlying block deices used for the PVs
 	// +kubebuilder:validation:Enum=nvme
@@ -82,6 +82,32 @@ type ClientMountDeviceReference struct {
 	Data int `json:"data,omitempty"`
 }
 
+// ClientMountDeviceNFS defines an NFS export to mount
+type ClientMountDeviceNFS struct {
+	// Host name or address of the NFS server
+	Server string `json:"server"`
+
+	// Path of the export on the NFS server
+	Export string `json:"export"`
+}
+
+// ClientMountDeviceGeneric defines a device that is mounted by giving its source and
+// file system type to mount, such as a GPFS file system
+type ClientMountDeviceGeneric struct {
+	// Source of the mount, as it is given to mount
+	Source string `json:"source"`
+
+	// File system type of the mount, as it is given to mount with -t
+	FileSystemType string `json:"fileSystemType"`
+}
+
+// ClientMountDeviceBind defines a directory or file on the client node that is bind
+// mounted at the mount path
+type ClientMountDeviceBind struct {
+	// Absolute path of the directory or file to bind mount
+	SourcePath string `json:"sourcePath"`
+}
+
 // ClientMountDeviceType specifies the go type for device type
 type ClientMountDeviceType string
 
@@ -96,11 +122,22 @@ const (
 	// a separate Kubernetes resource. The clientmountd (or another controller doing the mounts)
 	// must know how to interpret the resource to extract the device information.
 	ClientMountDeviceTypeReference ClientMountDeviceType = "reference"
+
+	// ClientMountDeviceTypeNFS is used to define the device as an NFS export
+	ClientMountDeviceTypeNFS ClientMountDeviceType = "nfs"
+
+	// ClientMountDeviceTypeGeneric is used to define the device by the source and file
+	// system type that are passed to mount
+	ClientMountDeviceTypeGeneric ClientMountDeviceType = "generic"
+
+	// ClientMountDeviceTypeBind is used to define the device as a directory or file on the
+	// client node that is bind mounted
+	ClientMountDeviceTypeBind ClientMountDeviceType = "bind"
 )
 
 // ClientMountDevice defines the device to mount
 type ClientMountDevice struct {
-	// +kubebuilder:validation:Enum=lustre;lvm;reference
+	// +kubebuilder:validation:Enum=lustre;lvm;reference;nfs;generic;bind
 	Type ClientMountDeviceType `json:"type"`
 
 	// Lustre specific device information
@@ -110,6 +147,15 @@ type ClientMountDevice struct {
 	LVM *ClientMountDeviceLVM `json:"lvm,omitempty"`
 
 	DeviceReference *ClientMountDeviceReference `json:"deviceReference,omitempty"`
+
+	// NFS specific device information
+	NFS *ClientMountDeviceNFS `json:"nfs,omitempty"`
+
+	// Generic device information for file systems without their own device type
+	Generic *ClientMountDeviceGeneric `json:"generic,omitempty"`
+
+	// Bind mount specific device information
+	Bind *ClientMountDeviceBind `json:"bind,omitempty"`
 }
 
 // ClientMountInfo defines a single mount
@@ -132,8 +178,10 @@ type ClientMountInfo struct {
 	// Description of the device to mount
 	Device ClientMountDevice `json:"device"`
 
-	// mount type
-	// +kubebuilder:validation:Enum=lustre;xfs;gfs2;none
+	// mount type. Generic devices are mounted with the file system type of the device,
+	// so any file system type name is allowed (e.g., lustre, xfs, gfs2, nfs, ext4). Bind
+	// mounts use none.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9][a-z0-9._-]*$`
 	Type string `json:"type"`
 
 	// TargetType determines whether the mount target is a file or a directory
@@ -153,6 +201,23 @@ const (
 	ClientMountStateUnmounted ClientMountState = "unmounted"
 )
 
+// ClientMountUnmountPolicy specifies how mounts are unmounted while files on them are in use
+type ClientMountUnmountPolicy string
+
+const (
+	// ClientMountUnmountPolicyNormal unmounts only once no files on the mount are in use.
+	// The unmount is retried until then.
+	ClientMountUnmountPolicyNormal ClientMountUnmountPolicy = "Normal"
+
+	// ClientMountUnmountPolicyLazy detaches the mount right away and cleans it up once
+	// its files are no longer in use
+	ClientMountUnmountPolicyLazy ClientMountUnmountPolicy = "Lazy"
+
+	// ClientMountUnmountPolicyForce forces the unmount even if files are in use. Not all
+	// file systems support this.
+	ClientMountUnmountPolicyForce ClientMountUnmountPolicy = "Force"
+)
+
 // ClientMountSpec defines the desired state of ClientMount
 type ClientMountSpec struct {
 	// Name of the client node that is targeted by this mount
@@ -162,9 +227,19 @@ type ClientMountSpec struct {
 	// +kubebuilder:validation:Enum=mounted;unmounted
 	DesiredState ClientMountState `json:"desiredState"`
 
-	// List of mounts to create on this client
+	// List of mounts to create on this client. A mount nested inside the mount path of
+	// another mount, or bind mounting a path inside it, is mounted after that mount and
+	// unmounted before it. Apart from that, the mounts are mounted in the order they are
+	// listed, and unmounted in the reverse of the order they were mounted.
 	// +kubebuilder:validation:MinItems=1
 	Mounts []ClientMountInfo `json:"mounts"`
+
+	// UnmountPolicy controls what happens when a mount is unmounted while files on it are
+	// still in use
+	// +kubebuilder:validation:Enum=Normal;Lazy;Force
+	// +kubebuilder:default:=Normal
+	// +optional
+	UnmountPolicy ClientMountUnmountPolicy `json:"unmountPolicy,omitempty"`
 }
 
 // ClientMountInfoStatus is the status for a single mount point
@@ -175,6 +250,22 @@ type ClientMountInfoStatus struct {
 
 	// Ready indicates whether status.state has been achieved
 	Ready bool `json:"ready"`
+
+	// Message describes what is happening with the mount, such as why it isn't ready
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Busy is true while the mount can't be unmounted because files on it are in use
+	// +optional
+	Busy bool `json:"busy,omitempty"`
+
+	// Error is the error from the last attempt to change the mount, if it failed
+	// +optional
+	Error *ResourceErrorInfo `json:"error,omitempty"`
+
+	// LastTransitionTime is the last time the state or the ready value of the mount changed
+	// +optional
+	LastTransitionTime *metav1.MicroTime `json:"lastTransitionTime,omitempty"`
 }
 
 // ClientMountStatus defines the observed state of ClientMount
@@ -185,8 +276,15 @@ type ClientMountStatus struct {
 	// Rollup of each mounts ready status
 	AllReady bool `json:"allReady"`
 
-	// Error information
+	// Error information. When mounts have failed, this is derived from their errors.
 	ResourceError `json:",inline"`
+
+	// Conditions summarizes the status of the mounts with the Ready, Degraded, and
+	// Error conditions.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 //+kubebuilder:object:root=true
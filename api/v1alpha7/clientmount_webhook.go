@@ -20,8 +20,17 @@
 package v1alpha7
 
 import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 // log is for logging in this package.
@@ -34,4 +43,205 @@ func (r *ClientMount) SetupWebhookWithManager(mgr ctrl.Manager) error {
 		Complete()
 }
 
-// TODO(user): EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+//+kubebuilder:webhook:path=/validate-dataworkflowservices-github-io-v1alpha7-clientmount,mutating=false,failurePolicy=fail,sideEffects=None,groups=dataworkflowservices.github.io,resources=clientmounts,verbs=create;update,versions=v1alpha7,name=vclientmount.kb.io,admissionReviewVersions={v1,v1beta1}
+
+var _ webhook.Validator = &ClientMount{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *ClientMount) ValidateCreate() (admission.Warnings, error) {
+	clientmountlog.Info("validate create", "name", r.Name)
+
+	return nil, r.validateSpec(nil)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *ClientMount) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	oldClientMount, ok := old.(*ClientMount)
+	if !ok {
+		err := fmt.Errorf("invalid ClientMount resource")
+		clientmountlog.Error(err, "old runtime.Object is not a ClientMount resource")
+
+		return nil, err
+	}
+
+	return nil, r.validateSpec(oldClientMount)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *ClientMount) ValidateDelete() (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateSpec checks the ClientMount spec. The node doing the mounts can't tell the
+// admin what's wrong with a spec until it tries to use it, so the mounts are checked
+// here instead. The old ClientMount is nil on create. On update, the mounts are only
+// checked when they change so that a ClientMount created before this validation
+// existed can still have its finalizers removed.
+func (r *ClientMount) validateSpec(old *ClientMount) error {
+	errList := field.ErrorList{}
+	specPath := field.NewPath("spec")
+
+	if r.Spec.Node == "" {
+		errList = append(errList, field.Required(specPath.Child("node"), "the client node must be named"))
+	}
+
+	if old != nil && r.Spec.Node != old.Spec.Node {
+		errList = append(errList, field.Forbidden(specPath.Child("node"), "field is immutable"))
+	}
+
+	if old != nil && reflect.DeepEqual(r.Spec.Mounts, old.Spec.Mounts) {
+		return r.invalid(errList)
+	}
+
+	// The mount paths seen so far, mapped to the index of their mount
+	mountPaths := map[string]int{}
+	for i, mount := range r.Spec.Mounts {
+		path := specPath.Child("mounts").Index(i)
+
+		errList = append(errList, validateMountPath(path.Child("mountPath"), mount.MountPath)...)
+		errList = append(errList, validateMountDevice(path.Child("device"), mount.Device)...)
+
+		if mount.MountPath == "" {
+			continue
+		}
+
+		if other, found := mountPaths[mount.MountPath]; found {
+			errList = append(errList, field.Duplicate(path.Child("mountPath"), fmt.Sprintf("%s is also used by mount %d", mount.MountPath, other)))
+			continue
+		}
+		mountPaths[mount.MountPath] = i
+	}
+
+	return r.invalid(errList)
+}
+
+// invalid returns an Invalid error for the problems in the list, or nil if there are none
+func (r *ClientMount) invalid(errList field.ErrorList) error {
+	if len(errList) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(GroupVersion.WithKind("ClientMount").GroupKind(), r.Name, errList)
+}
+
+// validateMountPath checks that a mount path is an absolute path to something other
+// than the root directory, and that it is already in its clean form. A path such as
+// "/mnt/../mnt/x" or "/mnt/x/" would otherwise be a second name for another mount's path.
+func validateMountPath(path *field.Path, mountPath string) field.ErrorList {
+	switch {
+	case mountPath == "":
+		return field.ErrorList{field.Required(path, "a mount path is required")}
+	case !filepath.IsAbs(mountPath):
+		return field.ErrorList{field.Invalid(path, mountPath, "the mount path must be absolute")}
+	case mountPath == "/":
+		return field.ErrorList{field.Invalid(path, mountPath, "the root directory may not be used as a mount path")}
+	case filepath.Clean(mountPath) != mountPath:
+		return field.ErrorList{field.Invalid(path, mountPath, fmt.Sprintf("the mount path must be written as %s", filepath.Clean(mountPath)))}
+	}
+
+	return nil
+}
+
+// validateMountDevice checks that the device has the information needed for its type,
+// and no information for the other types
+func validateMountDevice(path *field.Path, device ClientMountDevice) field.ErrorList {
+	errList := field.ErrorList{}
+
+	payloads := map[ClientMountDeviceType]bool{
+		ClientMountDeviceTypeLustre:    device.Lustre != nil,
+		ClientMountDeviceTypeLVM:       device.LVM != nil,
+		ClientMountDeviceTypeReference: device.DeviceReference != nil,
+		ClientMountDeviceTypeNFS:       device.NFS != nil,
+		ClientMountDeviceTypeGeneric:   device.Generic != nil,
+		ClientMountDeviceTypeBind:      device.Bind != nil,
+	}
+	children := map[ClientMountDeviceType]string{
+		ClientMountDeviceTypeLustre:    "lustre",
+		ClientMountDeviceTypeLVM:       "lvm",
+		ClientMountDeviceTypeReference: "deviceReference",
+		ClientMountDeviceTypeNFS:       "nfs",
+		ClientMountDeviceTypeGeneric:   "generic",
+		ClientMountDeviceTypeBind:      "bind",
+	}
+
+	deviceTypes := []ClientMountDeviceType{
+		ClientMountDeviceTypeLustre,
+		ClientMountDeviceTypeLVM,
+		ClientMountDeviceTypeReference,
+		ClientMountDeviceTypeNFS,
+		ClientMountDeviceTypeGeneric,
+		ClientMountDeviceTypeBind,
+	}
+
+	for _, deviceType := range deviceTypes {
+		child := path.Child(children[deviceType])
+		if deviceType == device.Type && !payloads[deviceType] {
+			errList = append(errList, field.Required(child, fmt.Sprintf("a %s device must have %s information", device.Type, children[deviceType])))
+		} else if deviceType != device.Type && payloads[deviceType] {
+			errList = append(errList, field.Forbidden(child, fmt.Sprintf("a %s device may not have %s information", device.Type, children[deviceType])))
+		}
+	}
+
+	switch device.Type {
+	case ClientMountDeviceTypeLustre:
+		if device.Lustre == nil {
+			break
+		}
+		if device.Lustre.FileSystemName == "" {
+			errList = append(errList, field.Required(path.Child("lustre", "fileSystemName"), "the file system name is required"))
+		}
+		if device.Lustre.MgsAddresses == "" {
+			errList = append(errList, field.Required(path.Child("lustre", "mgsAddresses"), "the MGS addresses are required"))
+		}
+	case ClientMountDeviceTypeLVM:
+		if device.LVM == nil {
+			break
+		}
+		if device.LVM.DeviceType == ClientMountLVMDeviceTypeNVMe && len(device.LVM.NVMeInfo) == 0 {
+			errList = append(errList, field.Required(path.Child("lvm", "nvmeInfo"), "an NVMe backed volume group must list its NVMe namespaces"))
+		}
+		if device.LVM.VolumeGroup == "" {
+			errList = append(errList, field.Required(path.Child("lvm", "volumeGroup"), "the volume group is required"))
+		}
+		if device.LVM.LogicalVolume == "" {
+			errList = append(errList, field.Required(path.Child("lvm", "logicalVolume"), "the logical volume is required"))
+		}
+	case ClientMountDeviceTypeReference:
+		if device.DeviceReference == nil {
+			break
+		}
+		if device.DeviceReference.ObjectReference.Name == "" {
+			errList = append(errList, field.Required(path.Child("deviceReference", "objectReference", "name"), "the referenced object must be named"))
+		}
+	case ClientMountDeviceTypeNFS:
+		if device.NFS == nil {
+			break
+		}
+		if device.NFS.Server == "" {
+			errList = append(errList, field.Required(path.Child("nfs", "server"), "the NFS server is required"))
+		}
+		if !filepath.IsAbs(device.NFS.Export) {
+			errList = append(errList, field.Invalid(path.Child("nfs", "export"), device.NFS.Export, "the export must be an absolute path"))
+		}
+	case ClientMountDeviceTypeGeneric:
+		if device.Generic == nil {
+			break
+		}
+		if device.Generic.Source == "" {
+			errList = append(errList, field.Required(path.Child("generic", "source"), "the source is required"))
+		}
+		if device.Generic.FileSystemType == "" {
+			errList = append(errList, field.Required(path.Child("generic", "fileSystemType"), "the file system type is required"))
+		}
+	case ClientMountDeviceTypeBind:
+		if device.Bind == nil {
+			break
+		}
+		sourcePath := device.Bind.SourcePath
+		if !filepath.IsAbs(sourcePath) || filepath.Clean(sourcePath) != sourcePath {
+			errList = append(errList, field.Invalid(path.Child("bind", "sourcePath"), sourcePath, "the source path must be an absolute path in its clean form"))
+		}
+	}
+
+	return errList
+}
@@ -0,0 +1,167 @@
+/*
+ * Copyright 2025 Hewlett Packard Enterprise Development LP
+ * Other additional copyright holders may be indicated within.
+ *
+ * The entirety of this work is licensed under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ *
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha7
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("ClientMount Webhook", func() {
+
+	var (
+		clientMount *ClientMount
+	)
+
+	BeforeEach(func() {
+		clientMount = &ClientMount{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("c%s", uuid.NewString()[0:8]),
+				Namespace: metav1.NamespaceDefault,
+			},
+			Spec: ClientMountSpec{
+				Node:         "client-01",
+				DesiredState: ClientMountStateMounted,
+				Mounts: []ClientMountInfo{
+					{
+						MountPath: "/mnt/lustre",
+						Device: ClientMountDevice{
+							Type:   ClientMountDeviceTypeLustre,
+							Lustre: &ClientMountDeviceLustre{FileSystemName: "lus", MgsAddresses: "10.0.0.1@tcp"},
+						},
+						Type:       "lustre",
+						TargetType: "directory",
+					},
+					{
+						MountPath: "/mnt/xfs",
+						Device: ClientMountDevice{
+							Type: ClientMountDeviceTypeLVM,
+							LVM: &ClientMountDeviceLVM{
+								DeviceType:    ClientMountLVMDeviceTypeNVMe,
+								NVMeInfo:      []ClientMountNVMeDesc{{DeviceSerial: "S1", NamespaceID: "1", NamespaceGUID: "g1"}},
+								VolumeGroup:   "vg",
+								LogicalVolume: "lv",
+							},
+						},
+						Type:       "xfs",
+						TargetType: "directory",
+					},
+					{
+						MountPath: "/mnt/ref",
+						Device: ClientMountDevice{
+							Type: ClientMountDeviceTypeReference,
+							DeviceReference: &ClientMountDeviceReference{
+								ObjectReference: corev1.ObjectReference{Name: "device", Namespace: metav1.NamespaceDefault},
+							},
+						},
+						Type:       "none",
+						TargetType: "directory",
+					},
+					{
+						MountPath: "/mnt/nfs",
+						Device: ClientMountDevice{
+							Type: ClientMountDeviceTypeNFS,
+							NFS:  &ClientMountDeviceNFS{Server: "nfs-server", Export: "/export/scratch"},
+						},
+						Type:       "nfs",
+						TargetType: "directory",
+					},
+					{
+						MountPath: "/mnt/gpfs",
+						Device: ClientMountDevice{
+							Type:    ClientMountDeviceTypeGeneric,
+							Generic: &ClientMountDeviceGeneric{Source: "gpfs0", FileSystemType: "gpfs"},
+						},
+						Type:       "none",
+						TargetType: "directory",
+					},
+					{
+						MountPath: "/mnt/local",
+						Device: ClientMountDevice{
+							Type: ClientMountDeviceTypeBind,
+							Bind: &ClientMountDeviceBind{SourcePath: "/local/scratch"},
+						},
+						Type:       "none",
+						TargetType: "directory",
+					},
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		if clientMount != nil {
+			Expect(k8sClient.Delete(context.TODO(), clientMount)).To(Succeed())
+		}
+	})
+
+	It("Creates a valid ClientMount", func() {
+		Expect(k8sClient.Create(context.TODO(), clientMount)).To(Succeed())
+	})
+
+	DescribeTable("Fails to create an invalid ClientMount",
+		func(modify func(spec *ClientMountSpec)) {
+			modify(&clientMount.Spec)
+			err := k8sClient.Create(context.TODO(), clientMount)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue(), "error: %v", err)
+			clientMount = nil
+		},
+		Entry("When the node is empty", func(spec *ClientMountSpec) { spec.Node = "" }),
+		Entry("When the mount path is empty", func(spec *ClientMountSpec) { spec.Mounts[0].MountPath = "" }),
+		Entry("When the mount path is relative", func(spec *ClientMountSpec) { spec.Mounts[0].MountPath = "mnt/lustre" }),
+		Entry("When the mount path is the root", func(spec *ClientMountSpec) { spec.Mounts[0].MountPath = "/" }),
+		Entry("When the mount path isn't clean", func(spec *ClientMountSpec) { spec.Mounts[0].MountPath = "/mnt/../mnt/lustre" }),
+		Entry("When mount paths are repeated", func(spec *ClientMountSpec) { spec.Mounts[1].MountPath = "/mnt/lustre" }),
+		Entry("When a lustre device has no lustre information", func(spec *ClientMountSpec) { spec.Mounts[0].Device.Lustre = nil }),
+		Entry("When a lustre device has no file system name", func(spec *ClientMountSpec) { spec.Mounts[0].Device.Lustre.FileSystemName = "" }),
+		Entry("When a lustre device has lvm information", func(spec *ClientMountSpec) { spec.Mounts[0].Device.LVM = spec.Mounts[1].Device.LVM }),
+		Entry("When an lvm device has no lvm information", func(spec *ClientMountSpec) { spec.Mounts[1].Device.LVM = nil }),
+		Entry("When an lvm device has no NVMe namespaces", func(spec *ClientMountSpec) { spec.Mounts[1].Device.LVM.NVMeInfo = nil }),
+		Entry("When a reference device has no reference", func(spec *ClientMountSpec) { spec.Mounts[2].Device.DeviceReference = nil }),
+		Entry("When an nfs device has no server", func(spec *ClientMountSpec) { spec.Mounts[3].Device.NFS.Server = "" }),
+		Entry("When an nfs export is relative", func(spec *ClientMountSpec) { spec.Mounts[3].Device.NFS.Export = "export" }),
+		Entry("When an nfs device has generic information", func(spec *ClientMountSpec) { spec.Mounts[3].Device.Generic = spec.Mounts[4].Device.Generic }),
+		Entry("When a generic device has no file system type", func(spec *ClientMountSpec) { spec.Mounts[4].Device.Generic.FileSystemType = "" }),
+		Entry("When a bind device has no bind information", func(spec *ClientMountSpec) { spec.Mounts[5].Device.Bind = nil }),
+		Entry("When a bind source path is relative", func(spec *ClientMountSpec) { spec.Mounts[5].Device.Bind.SourcePath = "local/scratch" }),
+	)
+
+	It("Fails to change the node", func() {
+		Expect(k8sClient.Create(context.TODO(), clientMount)).To(Succeed())
+
+		clientMount.Spec.Node = "client-02"
+		err := k8sClient.Update(context.TODO(), clientMount)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue(), "error: %v", err)
+	})
+
+	It("Allows the desired state to change", func() {
+		Expect(k8sClient.Create(context.TODO(), clientMount)).To(Succeed())
+
+		clientMount.Spec.DesiredState = ClientMountStateUnmounted
+		Expect(k8sClient.Update(context.TODO(), clientMount)).To(Succeed())
+	})
+})
@@ -0,0 +1,101 @@
+/*
+ * Copyright 2025 Hewlett Packard Enterprise Development LP
+ * Other additional copyright holders may be indicated within.
+ *
+ * The entirety of this work is licensed under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ *
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha7
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Types of the conditions in the status.conditions list of the DWS resources. Not every
+// resource uses every condition type.
+const (
+	// ConditionTypeReady is true when the resource has reached its desired state
+	ConditionTypeReady = "Ready"
+
+	// ConditionTypeDriversHealthy is true when none of the drivers working on the current
+	// state of a Workflow are reporting a problem
+	ConditionTypeDriversHealthy = "DriversHealthy"
+
+	// ConditionTypeDegraded is true when the resource has a problem that may be recoverable
+	ConditionTypeDegraded = "Degraded"
+
+	// ConditionTypeError is true when the resource has a problem that will not recover
+	ConditionTypeError = "Error"
+)
+
+// Reasons used for conditions that are not in their problem state
+const (
+	ConditionReasonReady          = "Ready"
+	ConditionReasonNotReady       = "NotReady"
+	ConditionReasonDriversHealthy = "DriversHealthy"
+	ConditionReasonNoError        = "NoError"
+)
+
+// SetCondition adds the condition to the list of conditions, or updates it if a condition of the
+// same type is already present. The LastTransitionTime is only changed when the status of the
+// condition changes.
+func SetCondition(conditions *[]metav1.Condition, conditionType string, status bool, reason string, message string, generation int64) {
+	conditionStatus := metav1.ConditionFalse
+	if status {
+		conditionStatus = metav1.ConditionTrue
+	}
+
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             conditionStatus,
+		ObservedGeneration: generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// SetErrorConditions sets the Degraded and Error conditions from the resource error. A Major
+// error makes the resource Degraded, and a Fatal error puts it in Error. A Minor error is
+// expected to clear on its own and doesn't set either condition.
+func (e *ResourceError) SetErrorConditions(conditions *[]metav1.Condition, generation int64) {
+	if e.Error == nil {
+		SetCondition(conditions, ConditionTypeDegraded, false, ConditionReasonNoError, "", generation)
+		SetCondition(conditions, ConditionTypeError, false, ConditionReasonNoError, "", generation)
+		return
+	}
+
+	message := e.Error.UserMessage
+	if message == "" {
+		message = e.Error.DebugMessage
+	}
+
+	reason := string(e.Error.Type)
+	if reason == "" {
+		reason = string(TypeInternal)
+	}
+
+	if e.Error.Severity == SeverityMajor {
+		SetCondition(conditions, ConditionTypeDegraded, true, reason, message, generation)
+	} else {
+		SetCondition(conditions, ConditionTypeDegraded, false, ConditionReasonNoError, "", generation)
+	}
+
+	if e.Error.Severity == SeverityFatal {
+		SetCondition(conditions, ConditionTypeError, true, reason, message, generation)
+	} else {
+		SetCondition(conditions, ConditionTypeError, false, ConditionReasonNoError, "", generation)
+	}
+}
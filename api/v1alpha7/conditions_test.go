@@ -0,0 +1,66 @@
+/*
+ * Copyright 2025 Hewlett Packard Enterprise Development LP
+ * Other additional copyright holders may be indicated within.
+ *
+ * The entirety of this work is licensed under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ *
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha7
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Conditions", func() {
+	var conditions []metav1.Condition
+
+	BeforeEach(func() {
+		conditions = []metav1.Condition{}
+	})
+
+	It("keeps the transition time when the status doesn't change", func() {
+		SetCondition(&conditions, ConditionTypeReady, false, ConditionReasonNotReady, "", 1)
+		transitionTime := meta.FindStatusCondition(conditions, ConditionTypeReady).LastTransitionTime
+
+		SetCondition(&conditions, ConditionTypeReady, false, ConditionReasonNotReady, "still waiting", 2)
+		condition := meta.FindStatusCondition(conditions, ConditionTypeReady)
+		Expect(condition.LastTransitionTime).To(Equal(transitionTime))
+		Expect(condition.Message).To(Equal("still waiting"))
+		Expect(condition.ObservedGeneration).To(Equal(int64(2)))
+		Expect(conditions).To(HaveLen(1))
+	})
+
+	DescribeTable("sets the error conditions from the resource error",
+		func(severity ResourceErrorSeverity, degraded bool, fatal bool) {
+			resourceError := ResourceError{Error: NewResourceError("debug").WithUserMessage("user")}
+			resourceError.Error.Severity = severity
+			resourceError.SetErrorConditions(&conditions, 1)
+
+			Expect(meta.IsStatusConditionTrue(conditions, ConditionTypeDegraded)).To(Equal(degraded))
+			Expect(meta.IsStatusConditionTrue(conditions, ConditionTypeError)).To(Equal(fatal))
+
+			resourceError.Error = nil
+			resourceError.SetErrorConditions(&conditions, 1)
+			Expect(meta.IsStatusConditionFalse(conditions, ConditionTypeDegraded)).To(BeTrue())
+			Expect(meta.IsStatusConditionFalse(conditions, ConditionTypeError)).To(BeTrue())
+		},
+		Entry("minor severity", SeverityMinor, false, false),
+		Entry("major severity", SeverityMajor, true, false),
+		Entry("fatal severity", SeverityFatal, false, true),
+	)
+})
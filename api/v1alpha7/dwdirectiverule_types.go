@@ -24,12 +24,26 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const (
+	// DWDirectiveRuleStagedLabel marks a DWDirectiveRule that is staged. A staged rule is
+	// only used by the Workflows whose spec.directiveRuleSelector selects it, so a new
+	// version of a rule can be tried without affecting other jobs. The value is ignored.
+	DWDirectiveRuleStagedLabel = "dataworkflowservices.github.io/staged"
+)
+
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
 //+kubebuilder:object:root=true
 //+kubebuilder:storageversion
 
 // DWDirectiveRule is the Schema for the DWDirective API
+//
+// A Workflow's directives are validated with the DWDirectiveRules in the namespace of the
+// DWS controller together with the DWDirectiveRules in the Workflow's namespace. The rules
+// in the Workflow's namespace can add to the system rules but can't replace them. The
+// Workflow can narrow these down with spec.directiveRuleSelector. The spec may not be
+// changed while a Workflow has recorded the DWDirectiveRule in status.directiveRules;
+// new rules are staged in another DWDirectiveRule instead.
 type DWDirectiveRule struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -20,8 +20,21 @@
 package v1alpha7
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/DataWorkflowServices/dws/utils/dwdparse"
 )
 
 // log is for logging in this package.
@@ -31,7 +44,206 @@ var dwdirectiverulelog = logf.Log.WithName("dwdirectiverule-resource")
 func (r *DWDirectiveRule) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
+		WithValidator(&dwDirectiveRuleValidator{client: mgr.GetClient()}).
 		Complete()
 }
 
-// TODO(user): EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+//+kubebuilder:webhook:path=/validate-dataworkflowservices-github-io-v1alpha7-dwdirectiverule,mutating=false,failurePolicy=fail,sideEffects=None,groups=dataworkflowservices.github.io,resources=dwdirectiverules,verbs=create;update,versions=v1alpha7,name=vdwdirectiverule.kb.io,admissionReviewVersions={v1,v1beta1}
+
+// dwDirectiveRuleValidator validates DWDirectiveRules. The client is used to find the
+// other DWDirectiveRules that are used along with this one, and the Workflows that use it.
+type dwDirectiveRuleValidator struct {
+	client client.Reader
+}
+
+var _ admission.CustomValidator = &dwDirectiveRuleValidator{}
+
+// ValidateCreate implements admission.CustomValidator so a webhook will be registered for the type
+func (v *dwDirectiveRuleValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	r, ok := obj.(*DWDirectiveRule)
+	if !ok {
+		return nil, fmt.Errorf("expected a DWDirectiveRule but got a %T", obj)
+	}
+	dwdirectiverulelog.Info("validate create", "name", r.Name)
+
+	return nil, v.validateRules(ctx, r)
+}
+
+// ValidateUpdate implements admission.CustomValidator so a webhook will be registered for the type
+func (v *dwDirectiveRuleValidator) ValidateUpdate(ctx context.Context, oldObj runtime.Object, newObj runtime.Object) (admission.Warnings, error) {
+	r, ok := newObj.(*DWDirectiveRule)
+	if !ok {
+		return nil, fmt.Errorf("expected a DWDirectiveRule but got a %T", newObj)
+	}
+	dwdirectiverulelog.Info("validate update", "name", r.Name)
+
+	old, ok := oldObj.(*DWDirectiveRule)
+	if !ok {
+		return nil, fmt.Errorf("expected a DWDirectiveRule but got a %T", oldObj)
+	}
+
+	if !equality.Semantic.DeepEqual(r.Spec, old.Spec) {
+		if err := v.validateNotRecorded(ctx, r); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, v.validateRules(ctx, r)
+}
+
+// ValidateDelete implements admission.CustomValidator so a webhook will be registered for the type
+func (v *dwDirectiveRuleValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateNotRecorded checks that no Workflow has recorded the DWDirectiveRule in its
+// status. A Workflow keeps the rules it was created with, so those rules can't be changed
+// underneath it. New rules are staged in another DWDirectiveRule instead.
+func (v *dwDirectiveRuleValidator) validateNotRecorded(ctx context.Context, r *DWDirectiveRule) error {
+	if v.client == nil {
+		return nil
+	}
+
+	workflows := &WorkflowList{}
+	if err := v.client.List(ctx, workflows); err != nil {
+		return err
+	}
+
+	for _, workflow := range workflows.Items {
+		for _, ref := range workflow.Status.DirectiveRules {
+			if ref.Name == r.Name && ref.Namespace == r.Namespace {
+				return field.Forbidden(field.NewPath("spec"), fmt.Sprintf("the rules are recorded by Workflow %s/%s and may not be changed; stage a new DWDirectiveRule instead", workflow.Namespace, workflow.Name))
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateRules checks each of the rules, and checks that no two rules are for the same
+// command and driver label, either in this DWDirectiveRule or in another one that a
+// Workflow would use along with it. The rules in the namespace of the DWS controller are
+// used with the rules in every namespace, so a tenant can't shadow a system rule. A broken
+// rule would otherwise only be found when it fails every Workflow that uses its command. A
+// staged DWDirectiveRule is expected to repeat the rules it will replace, so it isn't
+// compared with the other DWDirectiveRules.
+func (v *dwDirectiveRuleValidator) validateRules(ctx context.Context, r *DWDirectiveRule) error {
+	errList := field.ErrorList{}
+	specPath := field.NewPath("spec")
+
+	// The command and driver label of each rule, mapped to the path of the rule
+	owners := map[string]string{}
+	ruleID := func(rule dwdparse.DWDirectiveRuleSpec, name string) string {
+		if rule.DriverLabel == "" {
+			rule.DriverLabel = name
+		}
+		return rule.Command + "/" + rule.DriverLabel
+	}
+
+	for i, rule := range r.Spec {
+		path := specPath.Index(i)
+		if rule.DriverLabel == "" {
+			rule.DriverLabel = r.Name
+		}
+
+		for _, problem := range dwdparse.CheckRule(rule) {
+			errList = append(errList, field.Invalid(path.Child(problem.Field), problem.Value, problem.Message))
+		}
+
+		errList = append(errList, validateWatchStates(path.Child("watchStates"), rule.WatchStates)...)
+
+		id := ruleID(rule, r.Name)
+		if other, found := owners[id]; found {
+			errList = append(errList, field.Duplicate(path, fmt.Sprintf("command '%s' with driver label '%s' is also in %s", rule.Command, rule.DriverLabel, other)))
+		}
+		owners[id] = path.String()
+	}
+
+	if v.client != nil && !isStaged(r) {
+		others, err := v.listRulesUsedWith(ctx, r)
+		if err != nil {
+			return err
+		}
+
+		for _, other := range others {
+			if (other.Name == r.Name && other.Namespace == r.Namespace) || isStaged(&other) {
+				continue
+			}
+
+			for i, rule := range r.Spec {
+				for _, otherRule := range other.Spec {
+					if ruleID(rule, r.Name) == ruleID(otherRule, other.Name) {
+						errList = append(errList, field.Duplicate(specPath.Index(i), fmt.Sprintf("command '%s' with the same driver label is also in DWDirectiveRule %s/%s", rule.Command, other.Namespace, other.Name)))
+					}
+				}
+			}
+		}
+	}
+
+	if len(errList) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(GroupVersion.WithKind("DWDirectiveRule").GroupKind(), r.Name, errList)
+}
+
+// listRulesUsedWith returns the DWDirectiveRules that a Workflow could use along with the
+// DWDirectiveRule. The rules in the namespace of the DWS controller are used with the rules
+// in any namespace, and the rules in any other namespace are used with the rules in their
+// own namespace and the namespace of the DWS controller.
+func (v *dwDirectiveRuleValidator) listRulesUsedWith(ctx context.Context, r *DWDirectiveRule) ([]DWDirectiveRule, error) {
+	podNamespace := os.Getenv("POD_NAMESPACE")
+	if r.Namespace == podNamespace {
+		rules := &DWDirectiveRuleList{}
+		if err := v.client.List(ctx, rules); err != nil {
+			return nil, err
+		}
+
+		return rules.Items, nil
+	}
+
+	namespaces := []string{r.Namespace}
+	if podNamespace != "" {
+		namespaces = append(namespaces, podNamespace)
+	}
+
+	items := []DWDirectiveRule{}
+	for _, namespace := range namespaces {
+		rules := &DWDirectiveRuleList{}
+		if err := v.client.List(ctx, rules, client.InNamespace(namespace)); err != nil {
+			return nil, err
+		}
+
+		items = append(items, rules.Items...)
+	}
+
+	return items, nil
+}
+
+// isStaged returns true if the DWDirectiveRule has the staged label
+func isStaged(r *DWDirectiveRule) bool {
+	_, staged := r.GetLabels()[DWDirectiveRuleStagedLabel]
+	return staged
+}
+
+// validateWatchStates checks that a comma separated list of watch states has only
+// workflow states, with none repeated
+func validateWatchStates(path *field.Path, watchStates string) field.ErrorList {
+	errList := field.ErrorList{}
+	if watchStates == "" {
+		return errList
+	}
+
+	seen := map[WorkflowState]bool{}
+	for _, s := range strings.Split(watchStates, ",") {
+		state := WorkflowState(s)
+		if !state.valid() {
+			errList = append(errList, field.Invalid(path, watchStates, fmt.Sprintf("'%s' is not a workflow state", s)))
+		} else if seen[state] {
+			errList = append(errList, field.Invalid(path, watchStates, fmt.Sprintf("state '%s' is repeated", s)))
+		}
+		seen[state] = true
+	}
+
+	return errList
+}
@@ -0,0 +1,108 @@
+/*
+ * Copyright 2025 Hewlett Packard Enterprise Development LP
+ * Other additional copyright holders may be indicated within.
+ *
+ * The entirety of this work is licensed under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ *
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha7
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/DataWorkflowServices/dws/utils/dwdparse"
+)
+
+var _ = Describe("DWDirectiveRule Webhook", func() {
+
+	var (
+		rule *DWDirectiveRule
+	)
+
+	BeforeEach(func() {
+		rule = &DWDirectiveRule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("r%s", uuid.NewString()[0:8]),
+				Namespace: metav1.NamespaceDefault,
+			},
+			Spec: []dwdparse.DWDirectiveRuleSpec{{
+				Command:     "rule_test",
+				WatchStates: "Proposal,Setup",
+				RuleDefs: []dwdparse.DWDirectiveRuleDef{
+					{Key: "name", Type: "string", Pattern: "^[a-z]+$", IsRequired: true},
+					{Key: "count", Type: "integer", Min: 1, Max: 10},
+				},
+			}},
+		}
+	})
+
+	AfterEach(func() {
+		if rule != nil {
+			Expect(k8sClient.Delete(context.TODO(), rule)).To(Succeed())
+		}
+	})
+
+	It("Creates a valid rule", func() {
+		Expect(k8sClient.Create(context.TODO(), rule)).To(Succeed())
+	})
+
+	DescribeTable("Fails to create an invalid rule",
+		func(modify func(spec *dwdparse.DWDirectiveRuleSpec)) {
+			modify(&rule.Spec[0])
+			err := k8sClient.Create(context.TODO(), rule)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue(), "error: %v", err)
+			rule = nil
+		},
+		Entry("When the key is a broken regex", func(spec *dwdparse.DWDirectiveRuleSpec) { spec.RuleDefs[0].Key = "*" }),
+		Entry("When the pattern is a broken regex", func(spec *dwdparse.DWDirectiveRuleSpec) { spec.RuleDefs[0].Pattern = "^[a-z" }),
+		Entry("When the type is unknown", func(spec *dwdparse.DWDirectiveRuleSpec) { spec.RuleDefs[0].Type = "text" }),
+		Entry("When min is greater than max", func(spec *dwdparse.DWDirectiveRuleSpec) { spec.RuleDefs[1].Min = 20 }),
+		Entry("When a watch state is unknown", func(spec *dwdparse.DWDirectiveRuleSpec) { spec.WatchStates = "Proposal,Stup" }),
+		Entry("When a watch state is repeated", func(spec *dwdparse.DWDirectiveRuleSpec) { spec.WatchStates = "Setup,Setup" }),
+		Entry("When the rule depends on itself", func(spec *dwdparse.DWDirectiveRuleSpec) { spec.DependsOn = []string{rule.Name} }),
+	)
+
+	It("Fails to create two rules for the same command and driver label", func() {
+		rule.Spec = append(rule.Spec, rule.Spec[0])
+		Expect(k8sClient.Create(context.TODO(), rule)).ShouldNot(Succeed())
+
+		rule.Spec[1].DriverLabel = "other-driver"
+		Expect(k8sClient.Create(context.TODO(), rule)).To(Succeed())
+	})
+
+	It("Fails to create a rule that conflicts with another DWDirectiveRule", func() {
+		Expect(k8sClient.Create(context.TODO(), rule)).To(Succeed())
+
+		conflict := &DWDirectiveRule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("r%s", uuid.NewString()[0:8]),
+				Namespace: metav1.NamespaceDefault,
+			},
+			Spec: []dwdparse.DWDirectiveRuleSpec{rule.Spec[0]},
+		}
+		conflict.Spec[0].DriverLabel = rule.Name
+
+		Eventually(func() error {
+			return k8sClient.Create(context.TODO(), conflict)
+		}).ShouldNot(Succeed())
+	})
+})
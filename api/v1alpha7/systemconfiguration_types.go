@@ -76,6 +76,20 @@ type SystemConfigurationSpec struct {
 	// ports can be reused immediately.
 	// +kubebuilder:default:=60
 	PortsCooldownInSeconds int `json:"portsCooldownInSeconds"`
+
+	// WorkflowStateTimeouts is the site's default list of timeouts for the workflow states.
+	// A Workflow that does not reach Ready within the timeout for its current state has its
+	// status set to Error. States that are not listed have no timeout. A Workflow may override
+	// these values with its own spec.stateTimeouts. These are only used from the
+	// SystemConfiguration named "default" in the "default" namespace.
+	WorkflowStateTimeouts []WorkflowStateTimeout `json:"workflowStateTimeouts,omitempty"`
+
+	// WorkflowMaxStateRetries is the number of times a WLM may retry the failed drivers in
+	// any one workflow state. A value of 0 disables retries. This is only used from the
+	// SystemConfiguration named "default" in the "default" namespace.
+	// +kubebuilder:default:=3
+	// +kubebuilder:validation:Minimum:=0
+	WorkflowMaxStateRetries int `json:"workflowMaxStateRetries"`
 }
 
 // SystemConfigurationStatus defines the status of SystemConfiguration
@@ -85,6 +99,13 @@ type SystemConfigurationStatus struct {
 
 	// Error information
 	ResourceError `json:",inline"`
+
+	// Conditions summarizes the status of the SystemConfiguration with the Ready, Degraded,
+	// and Error conditions.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -22,6 +22,7 @@ package v1alpha7
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/DataWorkflowServices/dws/utils/updater"
 	corev1 "k8s.io/api/core/v1"
@@ -77,6 +78,18 @@ func (s WorkflowState) next() WorkflowState {
 	panic(s)
 }
 
+// valid reports whether s is one of the workflow states
+func (s WorkflowState) valid() bool {
+	for t := StateProposal; ; t = t.next() {
+		if s == t {
+			return true
+		}
+		if t.last() {
+			return false
+		}
+	}
+}
+
 // Last reports whether the state s is the last state
 func (s WorkflowState) last() bool {
 	return s == StateTeardown
@@ -96,6 +109,30 @@ func (s WorkflowState) after(t WorkflowState) bool {
 	return false
 }
 
+// WorkflowStateTimeout is the amount of time a workflow may spend in a state
+// before the state is considered to be hung.
+type WorkflowStateTimeout struct {
+	// State is the workflow state that the timeout applies to
+	State WorkflowState `json:"state"`
+
+	// Timeout is the amount of time the workflow may spend in State without becoming
+	// ready, measured from the time of the desiredState change. A value of 0 disables
+	// the timeout for State.
+	Timeout metav1.Duration `json:"timeout"`
+}
+
+// StateTimeout returns the timeout for state s from the list of timeouts, and whether
+// the list has an entry for s
+func StateTimeout(timeouts []WorkflowStateTimeout, s WorkflowState) (time.Duration, bool) {
+	for _, timeout := range timeouts {
+		if timeout.State == s {
+			return timeout.Timeout.Duration, true
+		}
+	}
+
+	return 0, false
+}
+
 // Strings associated with workflow statuses
 const (
 	StatusPending            = "Pending"
@@ -105,6 +142,7 @@ const (
 	StatusTransientCondition = "TransientCondition"
 	StatusError              = "Error"
 	StatusDriverWait         = "DriverWait"
+	StatusSuspended          = "Suspended"
 )
 
 // ToStatus will return a Status* string that goes with
@@ -138,6 +176,53 @@ func SeverityStringToStatus(severity string) (string, error) {
 	}
 }
 
+// WorkflowCancel is a request to cancel the workflow
+type WorkflowCancel struct {
+	// Reason describes why the workflow is being cancelled
+	// +kubebuilder:validation:MinLength:=1
+	Reason string `json:"reason"`
+
+	// Hurry is copied to spec.hurry when the workflow is moved to Teardown
+	// +kubebuilder:default:=false
+	Hurry bool `json:"hurry,omitempty"`
+}
+
+// WorkflowDirectiveDefaults records the default argument values that the directive rules
+// supplied for one of the workflow's directives
+type WorkflowDirectiveDefaults struct {
+	// DWDIndex is the index of the directive in spec.dwDirectives
+	DWDIndex int `json:"dwdIndex"`
+
+	// Defaults maps each argument the directive didn't have to the default value it was given
+	Defaults map[string]string `json:"defaults"`
+}
+
+// WorkflowCancelStatus records the cancellation of the workflow
+type WorkflowCancelStatus struct {
+	// Reason describes why the workflow was cancelled
+	Reason string `json:"reason"`
+
+	// State is the state the workflow was in when it was cancelled
+	State WorkflowState `json:"state"`
+
+	// CancelTime is the time the controller accepted the cancellation
+	CancelTime metav1.MicroTime `json:"cancelTime"`
+}
+
+// WorkflowSuspendStatus records the suspension of the workflow
+type WorkflowSuspendStatus struct {
+	// SuspendTime is the time the controller suspended the workflow
+	SuspendTime metav1.MicroTime `json:"suspendTime"`
+
+	// Status is the workflow's status when it was suspended. It is restored when the
+	// workflow is resumed.
+	Status string `json:"status,omitempty"`
+
+	// Message is the workflow's message when it was suspended. It is restored when the
+	// workflow is resumed.
+	Message string `json:"message,omitempty"`
+}
+
 // WorkflowSpec defines the desired state of Workflow
 type WorkflowSpec struct {
 	// Desired state for the workflow to be in. Unless progressing to the teardown state,
@@ -175,9 +260,43 @@ type WorkflowSpec struct {
 
 	// List of #DW strings from a WLM job script
 	DWDirectives []string `json:"dwDirectives"`
+
+	// StateTimeouts overrides the site's workflow state timeouts from the SystemConfiguration
+	// for the states that are listed. If the workflow does not reach Ready within the timeout
+	// for its current state, the workflow's status is set to Error.
+	StateTimeouts []WorkflowStateTimeout `json:"stateTimeouts,omitempty"`
+
+	// Cancel requests that the workflow be cancelled. Drivers are told to abandon the work
+	// for the current state through status.cancelled, and the workflow is moved to Teardown
+	// once the drivers have finished or the cancel grace period has passed. Cancel may not
+	// be changed once it is set.
+	Cancel *WorkflowCancel `json:"cancel,omitempty"`
+
+	// Suspend freezes the workflow in its current state. The desired state can't be changed
+	// while the workflow is suspended, though suspend may be cleared in the same update that
+	// changes the desired state. The workflow's status is Suspended until it is resumed.
+	// +kubebuilder:default:=false
+	Suspend bool `json:"suspend,omitempty"`
+
+	// DirectiveRuleSelector selects the DWDirectiveRules used for the workflow's directives
+	// by their labels. Staged rules are only used when they are selected. When it is not
+	// set, all of the DWDirectiveRules that aren't staged are used. It may not be changed.
+	DirectiveRuleSelector *metav1.LabelSelector `json:"directiveRuleSelector,omitempty"`
+
+	// RetryCount is incremented by the WLM to retry the drivers that failed in the current
+	// state. The drivers in Error are reset to Pending so they can run again. It may only be
+	// incremented by one at a time, and only while the workflow's status is Error. The number
+	// of retries in each state is limited by the SystemConfiguration.
+	// +kubebuilder:validation:Minimum:=0
+	RetryCount int `json:"retryCount,omitempty"`
 }
 
 // WorkflowDriverStatus defines the status information provided by integration drivers.
+// The workflow controller also writes to the Status and Message of a driver's entry when
+// the driver's heartbeat goes stale: it sets TransientCondition, and then Error, with a
+// message saying the heartbeat is stale, and returns the entry to Running if the driver
+// resumes its heartbeat before reaching Error. A driver should expect these values to be
+// overwritten and must not rely on them staying as it last wrote them.
 type WorkflowDriverStatus struct {
 	DriverID string `json:"driverID"`
 	TaskID   string `json:"taskID"`
@@ -185,6 +304,8 @@ type WorkflowDriverStatus struct {
 
 	WatchState WorkflowState `json:"watchState"`
 
+	// LastHB is the time of the driver's most recent heartbeat, in seconds since the
+	// Unix epoch. Drivers that leave this at zero are not checked for a stale heartbeat.
 	LastHB    int64 `json:"lastHB"`
 	Completed bool  `json:"completed"`
 
@@ -203,6 +324,81 @@ type WorkflowDriverStatus struct {
 
 	// CompleteTime reflects the time that the workflow reconciler marks the driver complete
 	CompleteTime *metav1.MicroTime `json:"completeTime,omitempty"`
+
+	// DependsOn is the list of driver IDs that must complete their entries for WatchState
+	// before this driver may start its work for WatchState
+	DependsOn []string `json:"dependsOn,omitempty"`
+
+	// Blocked is true while any of the drivers in DependsOn have not completed their
+	// entries for WatchState. A driver must not start its work for WatchState while
+	// Blocked is true.
+	Blocked bool `json:"blocked,omitempty"`
+}
+
+// UpdateBlockedDrivers sets Blocked on each of the driver entries for state based on
+// whether the drivers it depends on have completed their entries for the same state
+func (s *WorkflowStatus) UpdateBlockedDrivers(state WorkflowState) {
+	completed := map[string]bool{}
+	for _, driver := range s.Drivers {
+		if driver.WatchState != state {
+			continue
+		}
+
+		// A driver may have more than one entry for a state, one for each of its
+		// directives. It's only complete when all of those entries are complete.
+		if done, found := completed[driver.DriverID]; found && !done {
+			continue
+		}
+		completed[driver.DriverID] = driver.Completed
+	}
+
+	for i := range s.Drivers {
+		driver := &s.Drivers[i]
+		if driver.WatchState != state {
+			continue
+		}
+
+		driver.Blocked = false
+		if driver.Completed {
+			continue
+		}
+
+		for _, dependency := range driver.DependsOn {
+			if done, found := completed[dependency]; found && !done {
+				driver.Blocked = true
+				break
+			}
+		}
+	}
+}
+
+// WorkflowStateHistory records how the workflow progressed through one of its states
+type WorkflowStateHistory struct {
+	// State is the workflow state that the entry describes
+	State WorkflowState `json:"state"`
+
+	// EnterTime is the time of the desiredState change to State
+	EnterTime metav1.MicroTime `json:"enterTime"`
+
+	// ReadyTime is the time the workflow reached Ready in State. This is not set if
+	// the workflow moved on from State without reaching Ready.
+	ReadyTime *metav1.MicroTime `json:"readyTime,omitempty"`
+
+	// ElapsedTime is the time from EnterTime until the workflow reached Ready in State
+	// or moved on to another state. This is empty while the workflow is still working
+	// on State.
+	ElapsedTime string `json:"elapsedTime,omitempty"`
+
+	// Status is the workflow's status when it reached Ready in State or moved on to
+	// another state
+	Status string `json:"status,omitempty"`
+
+	// Message is the workflow's message when it reached Ready in State or moved on to
+	// another state
+	Message string `json:"message,omitempty"`
+
+	// ForceReady is true if State was reached with ForceReady
+	ForceReady bool `json:"forceReady,omitempty"`
 }
 
 // WorkflowStatus defines the observed state of the Workflow
@@ -220,7 +416,8 @@ type WorkflowStatus struct {
 	// - DriverWait: The underlying drivers are currently running.
 	// - TransientCondition: A driver has encountered an error that might be recoverable.
 	// - Error: A driver has encountered an error that will not recover.
-	// +kubebuilder:validation:Enum=Completed;DriverWait;TransientCondition;Error
+	// - Suspended: The workflow is suspended and will not change state until it is resumed.
+	// +kubebuilder:validation:Enum=Completed;DriverWait;TransientCondition;Error;Suspended
 	Status string `json:"status,omitempty"`
 
 	// Message provides additional details on the current status of the resource
@@ -261,6 +458,48 @@ type WorkflowStatus struct {
 
 	// Duration of the last state change
 	ElapsedTimeLastState string `json:"elapsedTimeLastState,omitempty"`
+
+	// History has an entry for each of the states the workflow has been in, oldest
+	// first. The states can't be repeated, so the list is bounded by the number of
+	// workflow states.
+	// +kubebuilder:validation:MaxItems=7
+	History []WorkflowStateHistory `json:"history,omitempty"`
+
+	// Cancelled is set by the controller when it accepts a cancellation request from
+	// spec.cancel. Drivers working on the state in Cancelled.State should stop any
+	// long-running work, such as data movement, as soon as possible.
+	Cancelled *WorkflowCancelStatus `json:"cancelled,omitempty"`
+
+	// Suspended is set by the controller while the workflow is suspended
+	Suspended *WorkflowSuspendStatus `json:"suspended,omitempty"`
+
+	// StateTimeoutStart is the time that the timeout for the current state is measured
+	// from. It is the time of the desiredState change, moved later by any time the
+	// workflow has spent suspended in the current state. A retry restarts it.
+	StateTimeoutStart *metav1.MicroTime `json:"stateTimeoutStart,omitempty"`
+
+	// RetryCount is the value of spec.retryCount that the controller last acted on
+	RetryCount int `json:"retryCount,omitempty"`
+
+	// StateRetryCount is the number of retries that have been done in the current state
+	StateRetryCount int `json:"stateRetryCount,omitempty"`
+
+	// DirectiveRules lists the DWDirectiveRules that validated the workflow's directives
+	// when it was created, with the resource version of each. The workflow keeps using these
+	// rules for as long as it exists, and their specs may not be changed while it does.
+	DirectiveRules []corev1.ObjectReference `json:"directiveRules,omitempty"`
+
+	// DirectiveDefaults records the defaults from the directive rules that were in effect
+	// for the directives when the workflow was created. Drivers should use these rather than
+	// the current rules, which may have changed since.
+	DirectiveDefaults []WorkflowDirectiveDefaults `json:"directiveDefaults,omitempty"`
+
+	// Conditions summarizes the status of the workflow with the Ready, DriversHealthy,
+	// Degraded, and Error conditions.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // WorkflowTokenSecret contains a pointer to the Secret that has a per-Workflow
@@ -0,0 +1,59 @@
+/*
+ * Copyright 2025 Hewlett Packard Enterprise Development LP
+ * Other additional copyright holders may be indicated within.
+ *
+ * The entirety of this work is licensed under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ *
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha7
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Workflow driver dependencies", func() {
+	var status *WorkflowStatus
+
+	BeforeEach(func() {
+		status = &WorkflowStatus{
+			Drivers: []WorkflowDriverStatus{
+				{DriverID: "filesystem", DWDIndex: 0, WatchState: StateDataIn},
+				{DriverID: "filesystem", DWDIndex: 1, WatchState: StateDataIn},
+				{DriverID: "datamover", DWDIndex: 2, WatchState: StateDataIn, DependsOn: []string{"filesystem"}},
+				{DriverID: "datamover", DWDIndex: 2, WatchState: StateDataOut, DependsOn: []string{"filesystem"}},
+			},
+		}
+	})
+
+	It("blocks a driver until all the entries it depends on are complete", func() {
+		status.UpdateBlockedDrivers(StateDataIn)
+		Expect(status.Drivers[2].Blocked).To(BeTrue())
+
+		status.Drivers[0].Completed = true
+		status.UpdateBlockedDrivers(StateDataIn)
+		Expect(status.Drivers[2].Blocked).To(BeTrue())
+
+		status.Drivers[1].Completed = true
+		status.UpdateBlockedDrivers(StateDataIn)
+		Expect(status.Drivers[2].Blocked).To(BeFalse())
+	})
+
+	It("doesn't block on drivers that aren't registered for the state", func() {
+		status.UpdateBlockedDrivers(StateDataOut)
+		Expect(status.Drivers[3].Blocked).To(BeFalse())
+		Expect(status.Drivers[2].Blocked).To(BeFalse())
+	})
+})
@@ -25,10 +25,20 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"sort"
 	"strings"
+	"sync"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	toolscache "k8s.io/client-go/tools/cache"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -39,15 +49,33 @@ import (
 )
 
 //+kubebuilder:rbac:groups=dataworkflowservices.github.io,resources=dwdirectiverules,verbs=get;list;watch
+//+kubebuilder:rbac:groups=dataworkflowservices.github.io,resources=systemconfigurations,verbs=get;list;watch
 
 // log is for logging in this package.
 var workflowlog = logf.Log.WithName("workflow-resource")
 
 var c client.Client
 
+// defaultMaxStateRetries is used when there is no default SystemConfiguration
+const defaultMaxStateRetries = 3
+
 // SetupWebhookWithManager will setup the manager to manage the webhooks
 func (w *Workflow) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	c = mgr.GetClient()
+
+	// Drop the compiled rules of a DWDirectiveRule when it is deleted. Changes to a
+	// DWDirectiveRule are found by its resource version.
+	informer, err := mgr.GetCache().GetInformer(context.Background(), &DWDirectiveRule{})
+	if err != nil {
+		return err
+	}
+
+	if _, err := informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		DeleteFunc: directiveRules.forget,
+	}); err != nil {
+		return err
+	}
+
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(w).
 		Complete()
@@ -63,8 +91,15 @@ var _ webhook.Defaulter = &Workflow{}
 func (w *Workflow) Default() {
 	workflowlog.Info("default", "name", w.Name)
 
+	// The DWDirectiveRules are chosen here, and not by whoever created the workflow
+	w.Status.DirectiveRules = nil
 	_ = checkDirectives(w, &MutatingRuleParser{})
 
+	// Block the drivers that have to wait for other drivers in the same state
+	for _, driver := range w.Status.Drivers {
+		w.Status.UpdateBlockedDrivers(driver.WatchState)
+	}
+
 	if w.Status.Env == nil {
 		w.Status.Env = make(map[string]string)
 	}
@@ -93,11 +128,27 @@ func (w *Workflow) ValidateCreate() (admission.Warnings, error) {
 	if w.Spec.Hurry {
 		return nil, field.Forbidden(specPath.Child("Hurry"), "the hurry flag may not be set on creation")
 	}
+	if w.Spec.Cancel != nil {
+		return nil, field.Forbidden(specPath.Child("Cancel"), "a workflow may not be cancelled on creation")
+	}
 	if w.Status.State != "" {
 		return nil, field.Forbidden(field.NewPath("Status").Child("State"), "the status state may not be set on creation")
 	}
+	if err := validateStateTimeouts(specPath.Child("StateTimeouts"), w.Spec.StateTimeouts); err != nil {
+		return nil, err
+	}
+
+	if w.Spec.DirectiveRuleSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(w.Spec.DirectiveRuleSelector); err != nil {
+			return nil, field.Invalid(specPath.Child("DirectiveRuleSelector"), w.Spec.DirectiveRuleSelector, err.Error())
+		}
+	}
+
+	if err := checkDirectives(w, &ValidatingRuleParser{}); err != nil {
+		return nil, directiveErrors(w, err)
+	}
 
-	return nil, checkDirectives(w, &ValidatingRuleParser{})
+	return nil, nil
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
@@ -117,12 +168,26 @@ func (w *Workflow) ValidateUpdate(old runtime.Object) (admission.Warnings, error
 		return nil, field.Invalid(field.NewPath("Spec").Child("Hurry"), w.Spec.Hurry, s)
 	}
 
+	if err := validateStateTimeouts(field.NewPath("Spec").Child("StateTimeouts"), w.Spec.StateTimeouts); err != nil {
+		return nil, err
+	}
+
 	// Check that immutable fields haven't changed.
 	err := validateWorkflowImmutable(w, oldWorkflow)
 	if err != nil {
 		return nil, err
 	}
 
+	// The DWDirectiveRules are recorded when the workflow is created, and the workflow
+	// keeps using the same ones
+	if !equality.Semantic.DeepEqual(w.Status.DirectiveRules, oldWorkflow.Status.DirectiveRules) {
+		return nil, field.Forbidden(field.NewPath("Status").Child("DirectiveRules"), "field is immutable")
+	}
+
+	if err := validateRetry(w, oldWorkflow); err != nil {
+		return nil, err
+	}
+
 	// Initial setup of the Workflow by the dws controller requires setting the status
 	// state to proposal and adding a finalizer.
 	if oldWorkflow.Status.State == "" && w.Spec.DesiredState == StateProposal {
@@ -152,6 +217,10 @@ func (w *Workflow) ValidateUpdate(old runtime.Object) (admission.Warnings, error
 			if driverStatus.Error != "" {
 				return nil, driverError("driver cannot be completed when error is present")
 			}
+
+			if driverStatus.Blocked && !oldWorkflow.Status.Drivers[i].Completed {
+				return nil, driverError("driver cannot be completed while it is blocked by the drivers it depends on")
+			}
 		} else {
 			if oldWorkflow.Status.Drivers[i].Completed {
 				return nil, driverError("driver cannot change from completed state")
@@ -162,6 +231,17 @@ func (w *Workflow) ValidateUpdate(old runtime.Object) (admission.Warnings, error
 	oldState := oldWorkflow.Status.State
 	newState := w.Spec.DesiredState
 
+	// A suspended workflow must stay where it is
+	if w.Spec.Suspend && newState != oldWorkflow.Spec.DesiredState {
+		return nil, field.Invalid(field.NewPath("Spec").Child("DesiredState"), w.Spec.DesiredState, "the desired state may not be changed while the workflow is suspended")
+	}
+
+	// A cancelled workflow may only move to teardown
+	if w.Spec.Cancel != nil && newState != oldWorkflow.Spec.DesiredState && newState != StateTeardown {
+		s := fmt.Sprintf("a cancelled workflow may only move to %s", StateTeardown)
+		return nil, field.Invalid(field.NewPath("Spec").Child("DesiredState"), w.Spec.DesiredState, s)
+	}
+
 	// Progressing to teardown is allowed at any time, and changes to the
 	// Workflow that don't change the state are fine too (immutable fields were
 	// already checked)
@@ -216,6 +296,83 @@ func validateWorkflowImmutable(newWorkflow *Workflow, oldWorkflow *Workflow) err
 		return immutableError("DWDirectives")
 	}
 
+	if !reflect.DeepEqual(newWorkflow.Spec.DirectiveRuleSelector, oldWorkflow.Spec.DirectiveRuleSelector) {
+		return immutableError("DirectiveRuleSelector")
+	}
+
+	if oldWorkflow.Spec.Cancel != nil && !reflect.DeepEqual(newWorkflow.Spec.Cancel, oldWorkflow.Spec.Cancel) {
+		return immutableError("Cancel")
+	}
+
+	return nil
+}
+
+// validateRetry checks that a retry requested through spec.retryCount is allowed. The
+// retry count may only go up by one, and only while the workflow is in Error and has
+// retries left for its current state.
+func validateRetry(newWorkflow *Workflow, oldWorkflow *Workflow) error {
+	if newWorkflow.Spec.RetryCount == oldWorkflow.Spec.RetryCount {
+		return nil
+	}
+
+	path := field.NewPath("Spec").Child("RetryCount")
+	if newWorkflow.Spec.RetryCount != oldWorkflow.Spec.RetryCount+1 {
+		return field.Invalid(path, newWorkflow.Spec.RetryCount, "retryCount may only be incremented by one")
+	}
+
+	if oldWorkflow.Spec.RetryCount != oldWorkflow.Status.RetryCount {
+		return field.Forbidden(path, "the previous retry has not been started")
+	}
+
+	if newWorkflow.Spec.DesiredState != oldWorkflow.Spec.DesiredState {
+		return field.Forbidden(path, "a retry may not be combined with a change to the desired state")
+	}
+
+	if oldWorkflow.Status.Status != StatusError {
+		return field.Forbidden(path, fmt.Sprintf("a retry may only be requested when the workflow's status is %s", StatusError))
+	}
+
+	maxRetries, err := getMaxStateRetries()
+	if err != nil {
+		return field.InternalError(path, err)
+	}
+
+	if oldWorkflow.Status.StateRetryCount >= maxRetries {
+		return field.Forbidden(path, fmt.Sprintf("the maximum of %d retries in state %s has been reached", maxRetries, oldWorkflow.Status.State))
+	}
+
+	return nil
+}
+
+// getMaxStateRetries returns the number of retries allowed in each state from the
+// default SystemConfiguration
+func getMaxStateRetries() (int, error) {
+	systemConfiguration := &SystemConfiguration{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: "default", Namespace: "default"}, systemConfiguration); err != nil {
+		if apierrors.IsNotFound(err) {
+			return defaultMaxStateRetries, nil
+		}
+		return 0, err
+	}
+
+	return systemConfiguration.Spec.WorkflowMaxStateRetries, nil
+}
+
+// validateStateTimeouts checks that each state has at most one timeout and that
+// none of the timeouts are negative
+func validateStateTimeouts(path *field.Path, timeouts []WorkflowStateTimeout) error {
+	states := map[WorkflowState]bool{}
+	for i, timeout := range timeouts {
+		if states[timeout.State] {
+			return field.Duplicate(path.Index(i).Child("State"), timeout.State)
+		}
+		states[timeout.State] = true
+
+		if timeout.Timeout.Duration < 0 {
+			return field.Invalid(path.Index(i).Child("Timeout"), timeout.Timeout.Duration.String(), "timeout may not be negative")
+		}
+	}
+
 	return nil
 }
 
@@ -225,65 +382,227 @@ func checkDirectives(workflow *Workflow, ruleParser RuleParser) error {
 		return nil
 	}
 
-	if err := ruleParser.ReadRules(); err != nil {
+	if err := ruleParser.ReadRules(workflow); err != nil {
 		return err
 	}
 
 	// Forward the rule and directive index to the rule parsers matched directive handling
 	onValidDirectiveFunc := func(index int, rule dwdparse.DWDirectiveRuleSpec) {
-		ruleParser.MatchedDirective(workflow, rule.WatchStates, index, rule.DriverLabel)
+		ruleParser.MatchedDirective(workflow, index, rule)
 	}
 
-	return dwdparse.Validate(ruleParser.GetRuleList(), workflow.Spec.DWDirectives, onValidDirectiveFunc)
+	return ruleParser.GetRuleSet().Validate(workflow.Spec.DWDirectives, onValidDirectiveFunc)
+}
+
+// directiveErrors turns the problems found in the workflow's directives into an Invalid
+// error that has a cause for each of them. Other errors are returned unchanged.
+func directiveErrors(workflow *Workflow, err error) error {
+	var validationErrs dwdparse.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return err
+	}
+
+	path := field.NewPath("Spec").Child("DWDirectives")
+	errList := field.ErrorList{}
+	for _, verr := range validationErrs {
+		if verr.Index < 0 || verr.Index >= len(workflow.Spec.DWDirectives) {
+			errList = append(errList, field.Invalid(path, workflow.Spec.DWDirectives, verr.Message))
+			continue
+		}
+
+		if verr.Reason == dwdparse.ReasonMissingArgument {
+			errList = append(errList, field.Required(path.Index(verr.Index), verr.Message))
+		} else {
+			errList = append(errList, field.Invalid(path.Index(verr.Index), workflow.Spec.DWDirectives[verr.Index], verr.Message))
+		}
+	}
+
+	return apierrors.NewInvalid(GroupVersion.WithKind("Workflow").GroupKind(), workflow.Name, errList)
 }
 
 // RuleParser defines the interface a rule parser must provide
 // +kubebuilder:object:generate=false
 type RuleParser interface {
-	ReadRules() error
+	ReadRules(*Workflow) error
 	GetRuleList() []dwdparse.DWDirectiveRuleSpec
-	MatchedDirective(*Workflow, string, int, string)
+	GetRuleSet() *dwdparse.RuleSet
+	MatchedDirective(*Workflow, int, dwdparse.DWDirectiveRuleSpec)
 }
 
-// RuleList contains the rules to be applied for a particular driver
-// +kubebuilder:object:generate=false
-type RuleList struct {
-	rules []dwdparse.DWDirectiveRuleSpec
+// ruleCache holds the compiled rules of each DWDirectiveRule so they aren't compiled on
+// every admission request. An entry is compiled again when the resource version of its
+// DWDirectiveRule changes, and the DWDirectiveRule informer removes the entry when the
+// DWDirectiveRule is deleted.
+type ruleCache struct {
+	sync.Mutex
+	entries map[types.NamespacedName]ruleCacheEntry
+}
+
+type ruleCacheEntry struct {
+	resourceVersion string
+	ruleSet         *dwdparse.RuleSet
 }
 
-// ReadRules imports the RulesList into usable go structures.
-func (r *RuleList) ReadRules() error {
-	ruleSetList := &DWDirectiveRuleList{}
-	ns := client.InNamespace(os.Getenv("POD_NAMESPACE"))
-	listOpts := []client.ListOption{
-		// Use all the DWDirectiveRules in the namespace we're running in
-		ns,
+var directiveRules ruleCache
+
+// get returns the compiled rules of a DWDirectiveRule
+func (rc *ruleCache) get(rule *DWDirectiveRule) *dwdparse.RuleSet {
+	rc.Lock()
+	defer rc.Unlock()
+
+	key := client.ObjectKeyFromObject(rule)
+	if entry, found := rc.entries[key]; found && entry.resourceVersion == rule.ResourceVersion {
+		return entry.ruleSet
 	}
 
-	if err := c.List(context.TODO(), ruleSetList, listOpts...); err != nil {
-		return err
+	rules := []dwdparse.DWDirectiveRuleSpec{}
+	for _, spec := range rule.Spec {
+		if spec.DriverLabel == "" {
+			spec.DriverLabel = rule.Name
+		}
+		rules = append(rules, spec)
 	}
 
-	if len(ruleSetList.Items) == 0 {
-		return fmt.Errorf("unable to find ruleset in namespace: %s", ns)
+	if rc.entries == nil {
+		rc.entries = map[types.NamespacedName]ruleCacheEntry{}
 	}
 
-	r.rules = []dwdparse.DWDirectiveRuleSpec{}
-	for _, ruleSet := range ruleSetList.Items {
-		for _, rule := range ruleSet.Spec {
-			if rule.DriverLabel == "" {
-				rule.DriverLabel = ruleSet.Name
+	ruleSet := dwdparse.NewRuleSet(rules)
+	rc.entries[key] = ruleCacheEntry{resourceVersion: rule.ResourceVersion, ruleSet: ruleSet}
+
+	return ruleSet
+}
+
+// forget removes the compiled rules of a deleted DWDirectiveRule
+func (rc *ruleCache) forget(obj interface{}) {
+	if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+
+	rule, ok := obj.(*DWDirectiveRule)
+	if !ok {
+		return
+	}
+
+	rc.Lock()
+	defer rc.Unlock()
+
+	delete(rc.entries, client.ObjectKeyFromObject(rule))
+}
+
+// selectDirectiveRules returns the DWDirectiveRules for a workflow. A workflow that has
+// already recorded its DWDirectiveRules gets those same ones, and it's an error if any of
+// them has changed since it was recorded. Otherwise the rules are
+// chosen by the workflow's directiveRuleSelector, or are all the rules that aren't staged,
+// from the namespace we're running in and from the workflow's namespace. The rules from
+// both namespaces are used together, so the rules in the workflow's namespace can add to
+// the system rules but can't replace them.
+func selectDirectiveRules(workflow *Workflow) ([]DWDirectiveRule, error) {
+	if len(workflow.Status.DirectiveRules) != 0 {
+		rules := []DWDirectiveRule{}
+		for _, ref := range workflow.Status.DirectiveRules {
+			rule := DWDirectiveRule{}
+			if err := c.Get(context.TODO(), types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}, &rule); err != nil {
+				return nil, fmt.Errorf("unable to get DWDirectiveRule %s/%s recorded by the workflow: %w", ref.Namespace, ref.Name, err)
 			}
-			r.rules = append(r.rules, rule)
+			if rule.ResourceVersion != ref.ResourceVersion {
+				return nil, fmt.Errorf("DWDirectiveRule %s/%s has changed since it was recorded by the workflow", ref.Namespace, ref.Name)
+			}
+			rules = append(rules, rule)
+		}
+
+		return rules, nil
+	}
+
+	selector, err := directiveRuleSelector(workflow)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := []string{}
+	if podNamespace := os.Getenv("POD_NAMESPACE"); podNamespace != "" && podNamespace != workflow.Namespace {
+		namespaces = append(namespaces, podNamespace)
+	}
+	namespaces = append(namespaces, workflow.Namespace)
+
+	rules := []DWDirectiveRule{}
+	for _, namespace := range namespaces {
+		ruleList := &DWDirectiveRuleList{}
+		if err := c.List(context.TODO(), ruleList, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return nil, err
 		}
+
+		// Keep the order of the rules stable from one workflow to the next
+		sort.Slice(ruleList.Items, func(i, j int) bool { return ruleList.Items[i].Name < ruleList.Items[j].Name })
+		rules = append(rules, ruleList.Items...)
+	}
+
+	if len(rules) != 0 {
+		return rules, nil
+	}
+
+	return nil, fmt.Errorf("unable to find DWDirectiveRules matching '%s' in namespaces: %s", selector, strings.Join(namespaces, ", "))
+}
+
+// directiveRuleSelector returns the label selector for the DWDirectiveRules of a workflow
+func directiveRuleSelector(workflow *Workflow) (labels.Selector, error) {
+	if workflow.Spec.DirectiveRuleSelector != nil {
+		return metav1.LabelSelectorAsSelector(workflow.Spec.DirectiveRuleSelector)
+	}
+
+	notStaged, err := labels.NewRequirement(DWDirectiveRuleStagedLabel, selection.DoesNotExist, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return labels.NewSelector().Add(*notStaged), nil
+}
+
+// RuleList contains the rules to be applied for a particular driver
+// +kubebuilder:object:generate=false
+type RuleList struct {
+	ruleSet *dwdparse.RuleSet
+	refs    []corev1.ObjectReference
+}
+
+// ReadRules imports the rules of the workflow's DWDirectiveRules into usable go structures.
+func (r *RuleList) ReadRules(workflow *Workflow) error {
+	rules, err := selectDirectiveRules(workflow)
+	if err != nil {
+		return err
 	}
 
+	ruleSets := []*dwdparse.RuleSet{}
+	r.refs = []corev1.ObjectReference{}
+	for i := range rules {
+		rule := &rules[i]
+		ruleSets = append(ruleSets, directiveRules.get(rule))
+		r.refs = append(r.refs, corev1.ObjectReference{
+			Kind:            "DWDirectiveRule",
+			APIVersion:      GroupVersion.String(),
+			Name:            rule.Name,
+			Namespace:       rule.Namespace,
+			ResourceVersion: rule.ResourceVersion,
+		})
+	}
+
+	r.ruleSet = dwdparse.MergeRuleSets(ruleSets...)
+
 	return nil
 }
 
 // GetRuleList returns the current rules
 func (r *RuleList) GetRuleList() []dwdparse.DWDirectiveRuleSpec {
-	return r.rules
+	if r.ruleSet == nil {
+		return nil
+	}
+
+	return r.ruleSet.Rules()
+}
+
+// GetRuleSet returns the compiled form of the current rules
+func (r *RuleList) GetRuleSet() *dwdparse.RuleSet {
+	return r.ruleSet
 }
 
 // MutatingRuleParser implements the RuleParser interface.
@@ -295,9 +614,29 @@ type MutatingRuleParser struct {
 	RuleList
 }
 
+// ReadRules reads the workflow's rules and records the DWDirectiveRules they came from
+// in the workflow's status while the workflow is being created
+func (r *MutatingRuleParser) ReadRules(workflow *Workflow) error {
+	if err := r.RuleList.ReadRules(workflow); err != nil {
+		return err
+	}
+
+	if workflow.Status.State == "" {
+		workflow.Status.DirectiveRules = r.refs
+	}
+
+	return nil
+}
+
 // MatchedDirective updates the driver status entries to indicate driver availability
-func (r *MutatingRuleParser) MatchedDirective(workflow *Workflow, watchStates string, index int, label string) {
-	if len(watchStates) == 0 {
+func (r *MutatingRuleParser) MatchedDirective(workflow *Workflow, index int, rule dwdparse.DWDirectiveRuleSpec) {
+	// The defaults are only recorded while the workflow is being created, so later changes
+	// to the rules don't change the meaning of its directives.
+	if workflow.Status.State == "" {
+		recordDirectiveDefaults(workflow, index, rule)
+	}
+
+	if len(rule.WatchStates) == 0 {
 		// Nothing to do
 		return
 	}
@@ -309,7 +648,7 @@ func (r *MutatingRuleParser) MatchedDirective(workflow *Workflow, watchStates st
 			continue
 		}
 
-		if s.DriverID != label {
+		if s.DriverID != rule.DriverLabel {
 			continue
 		}
 
@@ -317,7 +656,7 @@ func (r *MutatingRuleParser) MatchedDirective(workflow *Workflow, watchStates st
 	}
 
 	// Update driver status entries to indicate driver availability
-	for _, state := range strings.Split(watchStates, ",") {
+	for _, state := range strings.Split(rule.WatchStates, ",") {
 		state := WorkflowState(state)
 
 		// If this driver is already registered for this directive, skip it
@@ -327,16 +666,52 @@ func (r *MutatingRuleParser) MatchedDirective(workflow *Workflow, watchStates st
 
 		// Register states for this driver
 		driverStatus := WorkflowDriverStatus{
-			DriverID:   label,
+			DriverID:   rule.DriverLabel,
 			DWDIndex:   index,
 			WatchState: state,
 			Status:     StatusPending,
+			DependsOn:  rule.DependsOn,
 		}
 		workflow.Status.Drivers = append(workflow.Status.Drivers, driverStatus)
 		workflowlog.Info("Registering driver", "Driver", driverStatus.DriverID, "Watch state", state)
 	}
 }
 
+// recordDirectiveDefaults adds the defaults a rule provides for a directive to the
+// workflow's status
+func recordDirectiveDefaults(workflow *Workflow, index int, rule dwdparse.DWDirectiveRuleSpec) {
+	hasDefaults := false
+	for _, ruleDef := range rule.RuleDefs {
+		if ruleDef.Default != "" {
+			hasDefaults = true
+			break
+		}
+	}
+	if !hasDefaults {
+		return
+	}
+
+	defaults, err := dwdparse.Defaults(rule, workflow.Spec.DWDirectives[index])
+	if err != nil || len(defaults) == 0 {
+		return
+	}
+
+	for i := range workflow.Status.DirectiveDefaults {
+		entry := &workflow.Status.DirectiveDefaults[i]
+		if entry.DWDIndex == index {
+			for k, v := range defaults {
+				entry.Defaults[k] = v
+			}
+			return
+		}
+	}
+
+	workflow.Status.DirectiveDefaults = append(workflow.Status.DirectiveDefaults, WorkflowDirectiveDefaults{
+		DWDIndex: index,
+		Defaults: defaults,
+	})
+}
+
 // ValidatingRuleParser implements the RuleParser interface.
 var _ RuleParser = &ValidatingRuleParser{}
 
@@ -347,5 +722,5 @@ type ValidatingRuleParser struct {
 }
 
 // MatchedDirective provides the interface function for the validating webhook
-func (r *ValidatingRuleParser) MatchedDirective(workflow *Workflow, watchStates string, index int, label string) {
+func (r *ValidatingRuleParser) MatchedDirective(workflow *Workflow, index int, rule dwdparse.DWDirectiveRuleSpec) {
 }
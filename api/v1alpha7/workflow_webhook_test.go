@@ -22,11 +22,18 @@ package v1alpha7
 import (
 	"context"
 	"fmt"
+	"os"
+	"time"
 
 	"github.com/google/uuid"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/DataWorkflowServices/dws/utils/dwdparse"
 )
 
 // These tests are written in BDD-style using Ginkgo framework. Refer to
@@ -75,6 +82,271 @@ var _ = Describe("Workflow Webhook", func() {
 		workflow = nil
 	})
 
+	It("Creates workflow with state timeouts", func() {
+		workflow.Spec.StateTimeouts = []WorkflowStateTimeout{
+			{State: StateSetup, Timeout: metav1.Duration{Duration: 10 * time.Minute}},
+			{State: StateTeardown, Timeout: metav1.Duration{Duration: 0}},
+		}
+		Expect(k8sClient.Create(context.TODO(), workflow)).To(Succeed())
+	})
+
+	It("Fails to create workflow with a duplicate state timeout", func() {
+		workflow.Spec.StateTimeouts = []WorkflowStateTimeout{
+			{State: StateSetup, Timeout: metav1.Duration{Duration: 10 * time.Minute}},
+			{State: StateSetup, Timeout: metav1.Duration{Duration: 5 * time.Minute}},
+		}
+		Expect(k8sClient.Create(context.TODO(), workflow)).ShouldNot(Succeed())
+		workflow = nil
+	})
+
+	It("Fails to create workflow with a negative state timeout", func() {
+		workflow.Spec.StateTimeouts = []WorkflowStateTimeout{
+			{State: StateSetup, Timeout: metav1.Duration{Duration: -time.Minute}},
+		}
+		Expect(k8sClient.Create(context.TODO(), workflow)).ShouldNot(Succeed())
+		workflow = nil
+	})
+
+	It("Fails to create workflow with cancel set", func() {
+		workflow.Spec.Cancel = &WorkflowCancel{Reason: "job cancelled"}
+		Expect(k8sClient.Create(context.TODO(), workflow)).ShouldNot(Succeed())
+		workflow = nil
+	})
+
+	It("Fails to change cancel once it is set", func() {
+		Expect(k8sClient.Create(context.TODO(), workflow)).To(Succeed())
+
+		workflow.Spec.Cancel = &WorkflowCancel{Reason: "job cancelled"}
+		Expect(k8sClient.Update(context.TODO(), workflow)).To(Succeed())
+
+		workflow.Spec.Cancel.Reason = "something else"
+		Expect(k8sClient.Update(context.TODO(), workflow)).ShouldNot(Succeed())
+
+		workflow.Spec.Cancel = nil
+		Expect(k8sClient.Update(context.TODO(), workflow)).ShouldNot(Succeed())
+	})
+
+	It("Fails to retry a workflow that is not in Error", func() {
+		Expect(k8sClient.Create(context.TODO(), workflow)).To(Succeed())
+
+		workflow.Spec.RetryCount = 1
+		Expect(k8sClient.Update(context.TODO(), workflow)).ShouldNot(Succeed())
+	})
+
+	It("Fails to increment the retry count by more than one", func() {
+		Expect(k8sClient.Create(context.TODO(), workflow)).To(Succeed())
+
+		workflow.Status.Status = StatusError
+		Expect(k8sClient.Update(context.TODO(), workflow)).To(Succeed())
+
+		workflow.Spec.RetryCount = 2
+		Expect(k8sClient.Update(context.TODO(), workflow)).ShouldNot(Succeed())
+
+		workflow.Spec.RetryCount = 1
+		Expect(k8sClient.Update(context.TODO(), workflow)).To(Succeed())
+	})
+
+	It("Reports every problem in the directives", func() {
+		rule := &DWDirectiveRule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "webhook-test-rules",
+				Namespace: metav1.NamespaceDefault,
+			},
+			Spec: []dwdparse.DWDirectiveRuleSpec{{
+				Command: "jobdw",
+				RuleDefs: []dwdparse.DWDirectiveRuleDef{
+					{Key: "type", Type: "string", Pattern: "^(xfs|lustre)$", IsRequired: true, IsValueRequired: true},
+					{Key: "capacity", Type: "integer", Min: 1, IsRequired: true, IsValueRequired: true},
+					{Key: "name", Type: "string", IsRequired: true, IsValueRequired: true},
+				},
+			}},
+		}
+		Expect(k8sClient.Create(context.TODO(), rule)).To(Succeed())
+		DeferCleanup(func() { Expect(k8sClient.Delete(context.TODO(), rule)).To(Succeed()) })
+
+		workflow.Spec.DWDirectives = []string{
+			"#DW jobdw type=xfs capacity=10 name=good",
+			"#DW jobdw type=raw capacity=zero",
+		}
+
+		Eventually(func(g Gomega) {
+			err := k8sClient.Create(context.TODO(), workflow)
+			g.Expect(apierrors.IsInvalid(err)).To(BeTrue())
+
+			status, ok := err.(apierrors.APIStatus)
+			g.Expect(ok).To(BeTrue())
+			g.Expect(status.Status().Details.Causes).To(HaveLen(3))
+			for _, cause := range status.Status().Details.Causes {
+				g.Expect(cause.Field).To(Equal("Spec.DWDirectives[1]"))
+			}
+		}).Should(Succeed())
+		workflow = nil
+	})
+
+	It("Records the directive defaults", func() {
+		rule := &DWDirectiveRule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "webhook-default-rules",
+				Namespace: metav1.NamespaceDefault,
+			},
+			Spec: []dwdparse.DWDirectiveRuleSpec{{
+				Command: "jobdw",
+				RuleDefs: []dwdparse.DWDirectiveRuleDef{
+					{Key: "type", Type: "enum", Enum: []string{"xfs", "lustre"}, Default: "xfs"},
+					{Key: "name", Type: "string", IsRequired: true, IsValueRequired: true},
+				},
+			}},
+		}
+		Expect(k8sClient.Create(context.TODO(), rule)).To(Succeed())
+		DeferCleanup(func() { Expect(k8sClient.Delete(context.TODO(), rule)).To(Succeed()) })
+
+		workflow.Spec.DWDirectives = []string{
+			"#DW jobdw name=defaulted",
+			"#DW jobdw name=explicit type=lustre",
+		}
+
+		Eventually(func() error {
+			return k8sClient.Create(context.TODO(), workflow)
+		}).Should(Succeed())
+
+		Expect(workflow.Status.DirectiveDefaults).To(ConsistOf(WorkflowDirectiveDefaults{
+			DWDIndex: 0,
+			Defaults: map[string]string{"type": "xfs"},
+		}))
+	})
+
+	It("Uses staged rules only when the workflow selects them", func() {
+		rule := &DWDirectiveRule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "webhook-staged-rules",
+				Namespace: metav1.NamespaceDefault,
+				Labels: map[string]string{
+					DWDirectiveRuleStagedLabel: "true",
+					"version":                  "next",
+				},
+			},
+			Spec: []dwdparse.DWDirectiveRuleSpec{{
+				Command: "stagedcmd",
+				RuleDefs: []dwdparse.DWDirectiveRuleDef{
+					{Key: "name", Type: "string", IsRequired: true, IsValueRequired: true},
+				},
+			}},
+		}
+		Expect(k8sClient.Create(context.TODO(), rule)).To(Succeed())
+		DeferCleanup(func() { Expect(k8sClient.Delete(context.TODO(), rule)).To(Succeed()) })
+
+		workflow.Spec.DWDirectives = []string{"#DW stagedcmd name=staged"}
+		Expect(k8sClient.Create(context.TODO(), workflow)).ShouldNot(Succeed())
+
+		workflow.Spec.DirectiveRuleSelector = &metav1.LabelSelector{
+			MatchLabels: map[string]string{"version": "next"},
+		}
+		Eventually(func() error {
+			return k8sClient.Create(context.TODO(), workflow)
+		}).Should(Succeed())
+
+		Expect(workflow.Status.DirectiveRules).To(HaveLen(1))
+		Expect(workflow.Status.DirectiveRules[0].Name).To(Equal(rule.Name))
+		Expect(workflow.Status.DirectiveRules[0].Namespace).To(Equal(rule.Namespace))
+
+		workflow.Spec.DirectiveRuleSelector = nil
+		Expect(k8sClient.Update(context.TODO(), workflow)).ShouldNot(Succeed())
+	})
+
+	It("Uses the system rules along with the rules in the workflow's namespace", func() {
+		podNamespace, found := os.LookupEnv("POD_NAMESPACE")
+		Expect(os.Setenv("POD_NAMESPACE", metav1.NamespaceDefault)).To(Succeed())
+		DeferCleanup(func() {
+			if found {
+				Expect(os.Setenv("POD_NAMESPACE", podNamespace)).To(Succeed())
+			} else {
+				Expect(os.Unsetenv("POD_NAMESPACE")).To(Succeed())
+			}
+		})
+
+		tenant := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-" + uuid.NewString()[0:8]}}
+		Expect(k8sClient.Create(context.TODO(), tenant)).To(Succeed())
+
+		systemRule := &DWDirectiveRule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "webhook-system-rules",
+				Namespace: metav1.NamespaceDefault,
+			},
+			Spec: []dwdparse.DWDirectiveRuleSpec{{
+				Command: "tenantcmd",
+				RuleDefs: []dwdparse.DWDirectiveRuleDef{
+					{Key: "name", Type: "string", Pattern: "^[a-z]+$", IsRequired: true, IsValueRequired: true},
+				},
+			}},
+		}
+		Expect(k8sClient.Create(context.TODO(), systemRule)).To(Succeed())
+		DeferCleanup(func() { Expect(k8sClient.Delete(context.TODO(), systemRule)).To(Succeed()) })
+
+		tenantRule := &DWDirectiveRule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "webhook-tenant-rules",
+				Namespace: tenant.Name,
+			},
+			Spec: []dwdparse.DWDirectiveRuleSpec{{
+				Command: "tenantcmd",
+				RuleDefs: []dwdparse.DWDirectiveRuleDef{
+					{Key: "name", Type: "string", IsRequired: true, IsValueRequired: true},
+				},
+			}},
+		}
+		Expect(k8sClient.Create(context.TODO(), tenantRule)).To(Succeed())
+		DeferCleanup(func() { Expect(k8sClient.Delete(context.TODO(), tenantRule)).To(Succeed()) })
+
+		// Wait until the webhook sees both of the rules. A workflow that was created with
+		// only one of them is deleted and tried again.
+		workflow.Namespace = tenant.Name
+		workflow.Spec.DWDirectives = []string{"#DW tenantcmd name=good"}
+		template := workflow.DeepCopy()
+		Eventually(func(g Gomega) []corev1.ObjectReference {
+			workflow = template.DeepCopy()
+			workflow.Name = "w" + uuid.NewString()[0:8]
+			g.Expect(k8sClient.Create(context.TODO(), workflow)).To(Succeed())
+			if len(workflow.Status.DirectiveRules) != 2 {
+				g.Expect(k8sClient.Delete(context.TODO(), workflow)).To(Succeed())
+			}
+			return workflow.Status.DirectiveRules
+		}).Should(HaveLen(2))
+
+		// The tenant's rule accepts the name, but the system rule doesn't
+		bad := template.DeepCopy()
+		bad.Spec.DWDirectives = []string{"#DW tenantcmd name=Bad1"}
+		Expect(k8sClient.Create(context.TODO(), bad)).ShouldNot(Succeed())
+
+		Expect(workflow.Status.DirectiveRules[0].Name).To(Equal(systemRule.Name))
+		Expect(workflow.Status.DirectiveRules[1].Name).To(Equal(tenantRule.Name))
+
+		// The recorded rules can't be changed
+		workflow.Status.DirectiveRules = workflow.Status.DirectiveRules[1:]
+		Expect(k8sClient.Update(context.TODO(), workflow)).ShouldNot(Succeed())
+
+		// Nor can the DWDirectiveRules themselves while the workflow has them recorded
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(systemRule), systemRule)).To(Succeed())
+		systemRule.Spec[0].RuleDefs[0].Pattern = "^[a-zA-Z0-9]+$"
+		Expect(k8sClient.Update(context.TODO(), systemRule)).ShouldNot(Succeed())
+
+		// A tenant can't shadow a system rule with its own rule for the same command and
+		// driver label
+		shadowRule := &DWDirectiveRule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "webhook-shadow-rules",
+				Namespace: tenant.Name,
+			},
+			Spec: []dwdparse.DWDirectiveRuleSpec{{
+				Command:     "tenantcmd",
+				DriverLabel: systemRule.Name,
+				RuleDefs: []dwdparse.DWDirectiveRuleDef{
+					{Key: "name", Type: "string", IsRequired: true, IsValueRequired: true},
+				},
+			}},
+		}
+		Expect(k8sClient.Create(context.TODO(), shadowRule)).ShouldNot(Succeed())
+	})
+
 	DescribeTable("Workflow created only when Spec.DesiredState is Proposal",
 		func(desiredState WorkflowState, expectSuccess bool) {
 			workflow.Spec.DesiredState = desiredState
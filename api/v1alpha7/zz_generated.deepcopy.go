@@ -25,7 +25,8 @@ package v1alpha7
 
 import (
 	"github.com/DataWorkflowServices/dws/utils/dwdparse"
-	"k8s.io/api/core/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
@@ -115,6 +116,21 @@ func (in *ClientMountDevice) DeepCopyInto(out *ClientMountDevice) {
 		*out = new(ClientMountDeviceReference)
 		**out = **in
 	}
+	if in.NFS != nil {
+		in, out := &in.NFS, &out.NFS
+		*out = new(ClientMountDeviceNFS)
+		**out = **in
+	}
+	if in.Generic != nil {
+		in, out := &in.Generic, &out.Generic
+		*out = new(ClientMountDeviceGeneric)
+		**out = **in
+	}
+	if in.Bind != nil {
+		in, out := &in.Bind, &out.Bind
+		*out = new(ClientMountDeviceBind)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientMountDevice.
@@ -127,6 +143,36 @@ func (in *ClientMountDevice) DeepCopy() *ClientMountDevice {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientMountDeviceBind) DeepCopyInto(out *ClientMountDeviceBind) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientMountDeviceBind.
+func (in *ClientMountDeviceBind) DeepCopy() *ClientMountDeviceBind {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientMountDeviceBind)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientMountDeviceGeneric) DeepCopyInto(out *ClientMountDeviceGeneric) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientMountDeviceGeneric.
+func (in *ClientMountDeviceGeneric) DeepCopy() *ClientMountDeviceGeneric {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientMountDeviceGeneric)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClientMountDeviceLVM) DeepCopyInto(out *ClientMountDeviceLVM) {
 	*out = *in
@@ -162,6 +208,21 @@ func (in *ClientMountDeviceLustre) DeepCopy() *ClientMountDeviceLustre {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientMountDeviceNFS) DeepCopyInto(out *ClientMountDeviceNFS) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientMountDeviceNFS.
+func (in *ClientMountDeviceNFS) DeepCopy() *ClientMountDeviceNFS {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientMountDeviceNFS)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClientMountDeviceReference) DeepCopyInto(out *ClientMountDeviceReference) {
 	*out = *in
@@ -197,6 +258,15 @@ func (in *ClientMountInfo) DeepCopy() *ClientMountInfo {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClientMountInfoStatus) DeepCopyInto(out *ClientMountInfoStatus) {
 	*out = *in
+	if in.Error != nil {
+		in, out := &in.Error, &out.Error
+		*out = new(ResourceErrorInfo)
+		**out = **in
+	}
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientMountInfoStatus.
@@ -284,9 +354,18 @@ func (in *ClientMountStatus) DeepCopyInto(out *ClientMountStatus) {
 	if in.Mounts != nil {
 		in, out := &in.Mounts, &out.Mounts
 		*out = make([]ClientMountInfoStatus, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	in.ResourceError.DeepCopyInto(&out.ResourceError)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientMountStatus.
@@ -698,7 +777,7 @@ func (in *PersistentStorageInstanceSpec) DeepCopyInto(out *PersistentStorageInst
 	*out = *in
 	if in.ConsumerReferences != nil {
 		in, out := &in.ConsumerReferences, &out.ConsumerReferences
-		*out = make([]v1.ObjectReference, len(*in))
+		*out = make([]corev1.ObjectReference, len(*in))
 		copy(*out, *in)
 	}
 }
@@ -1235,6 +1314,11 @@ func (in *SystemConfigurationSpec) DeepCopyInto(out *SystemConfigurationSpec) {
 		*out = make([]intstr.IntOrString, len(*in))
 		copy(*out, *in)
 	}
+	if in.WorkflowStateTimeouts != nil {
+		in, out := &in.WorkflowStateTimeouts, &out.WorkflowStateTimeouts
+		*out = make([]WorkflowStateTimeout, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SystemConfigurationSpec.
@@ -1251,6 +1335,13 @@ func (in *SystemConfigurationSpec) DeepCopy() *SystemConfigurationSpec {
 func (in *SystemConfigurationStatus) DeepCopyInto(out *SystemConfigurationStatus) {
 	*out = *in
 	in.ResourceError.DeepCopyInto(&out.ResourceError)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SystemConfigurationStatus.
@@ -1390,6 +1481,59 @@ func (in *Workflow) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkflowCancel) DeepCopyInto(out *WorkflowCancel) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowCancel.
+func (in *WorkflowCancel) DeepCopy() *WorkflowCancel {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkflowCancel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkflowCancelStatus) DeepCopyInto(out *WorkflowCancelStatus) {
+	*out = *in
+	in.CancelTime.DeepCopyInto(&out.CancelTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowCancelStatus.
+func (in *WorkflowCancelStatus) DeepCopy() *WorkflowCancelStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkflowCancelStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkflowDirectiveDefaults) DeepCopyInto(out *WorkflowDirectiveDefaults) {
+	*out = *in
+	if in.Defaults != nil {
+		in, out := &in.Defaults, &out.Defaults
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowDirectiveDefaults.
+func (in *WorkflowDirectiveDefaults) DeepCopy() *WorkflowDirectiveDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkflowDirectiveDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkflowDriverStatus) DeepCopyInto(out *WorkflowDriverStatus) {
 	*out = *in
@@ -1397,6 +1541,11 @@ func (in *WorkflowDriverStatus) DeepCopyInto(out *WorkflowDriverStatus) {
 		in, out := &in.CompleteTime, &out.CompleteTime
 		*out = (*in).DeepCopy()
 	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowDriverStatus.
@@ -1450,6 +1599,21 @@ func (in *WorkflowSpec) DeepCopyInto(out *WorkflowSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.StateTimeouts != nil {
+		in, out := &in.StateTimeouts, &out.StateTimeouts
+		*out = make([]WorkflowStateTimeout, len(*in))
+		copy(*out, *in)
+	}
+	if in.Cancel != nil {
+		in, out := &in.Cancel, &out.Cancel
+		*out = new(WorkflowCancel)
+		**out = **in
+	}
+	if in.DirectiveRuleSelector != nil {
+		in, out := &in.DirectiveRuleSelector, &out.DirectiveRuleSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowSpec.
@@ -1462,6 +1626,42 @@ func (in *WorkflowSpec) DeepCopy() *WorkflowSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkflowStateHistory) DeepCopyInto(out *WorkflowStateHistory) {
+	*out = *in
+	in.EnterTime.DeepCopyInto(&out.EnterTime)
+	if in.ReadyTime != nil {
+		in, out := &in.ReadyTime, &out.ReadyTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowStateHistory.
+func (in *WorkflowStateHistory) DeepCopy() *WorkflowStateHistory {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkflowStateHistory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkflowStateTimeout) DeepCopyInto(out *WorkflowStateTimeout) {
+	*out = *in
+	out.Timeout = in.Timeout
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowStateTimeout.
+func (in *WorkflowStateTimeout) DeepCopy() *WorkflowStateTimeout {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkflowStateTimeout)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkflowStatus) DeepCopyInto(out *WorkflowStatus) {
 	*out = *in
@@ -1481,7 +1681,7 @@ func (in *WorkflowStatus) DeepCopyInto(out *WorkflowStatus) {
 	}
 	if in.DirectiveBreakdowns != nil {
 		in, out := &in.DirectiveBreakdowns, &out.DirectiveBreakdowns
-		*out = make([]v1.ObjectReference, len(*in))
+		*out = make([]corev1.ObjectReference, len(*in))
 		copy(*out, *in)
 	}
 	if in.Requires != nil {
@@ -1503,6 +1703,46 @@ func (in *WorkflowStatus) DeepCopyInto(out *WorkflowStatus) {
 		in, out := &in.ReadyChange, &out.ReadyChange
 		*out = (*in).DeepCopy()
 	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]WorkflowStateHistory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Cancelled != nil {
+		in, out := &in.Cancelled, &out.Cancelled
+		*out = new(WorkflowCancelStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Suspended != nil {
+		in, out := &in.Suspended, &out.Suspended
+		*out = new(WorkflowSuspendStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StateTimeoutStart != nil {
+		in, out := &in.StateTimeoutStart, &out.StateTimeoutStart
+		*out = (*in).DeepCopy()
+	}
+	if in.DirectiveRules != nil {
+		in, out := &in.DirectiveRules, &out.DirectiveRules
+		*out = make([]corev1.ObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.DirectiveDefaults != nil {
+		in, out := &in.DirectiveDefaults, &out.DirectiveDefaults
+		*out = make([]WorkflowDirectiveDefaults, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowStatus.
@@ -1515,6 +1755,22 @@ func (in *WorkflowStatus) DeepCopy() *WorkflowStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkflowSuspendStatus) DeepCopyInto(out *WorkflowSuspendStatus) {
+	*out = *in
+	in.SuspendTime.DeepCopyInto(&out.SuspendTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowSuspendStatus.
+func (in *WorkflowSuspendStatus) DeepCopy() *WorkflowSuspendStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkflowSuspendStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkflowTokenSecret) DeepCopyInto(out *WorkflowTokenSecret) {
 	*out = *in
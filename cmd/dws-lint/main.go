@@ -0,0 +1,274 @@
+/*
+ * Copyright 2025 Hewlett Packard Enterprise Development LP
+ * Other additional copyright holders may be indicated within.
+ *
+ * The entirety of this work is licensed under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ *
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// dws-lint checks the #DW directives of a job script against a set of DWDirectiveRules
+// without a cluster. It reports the rules each directive matched, the drivers and watch
+// states that the directive would register in a Workflow, and any errors.
+//
+// Usage:
+//
+//	dws-lint -rules rules.yaml [-rules more-rules.yaml] [-prefix #BB] [job-script ...]
+//
+// The job scripts are read from stdin when none are given, or when a script is "-".
+// Directives are reported with the line numbers where they start in the script.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	dwsv1alpha7 "github.com/DataWorkflowServices/dws/api/v1alpha7"
+	"github.com/DataWorkflowServices/dws/utils/dwdparse"
+	"github.com/DataWorkflowServices/dws/utils/dwdscript"
+)
+
+// stringList collects the values of a repeated flag
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+func main() {
+	os.Exit(run(os.Args[0], os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs dws-lint with the command line arguments and returns the exit code. The exit
+// code is 0 when the rules and the directives are valid, 1 when any of them aren't, and
+// 2 when dws-lint couldn't do the check.
+func run(command string, args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	var files stringList
+	var prefixes stringList
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Var(&files, "rules", "YAML file of DWDirectiveRule resources. May be repeated.")
+	flags.Var(&prefixes, "prefix", "Prefix of the directive lines in the job scripts. May be repeated. (default \"#DW\")")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s -rules FILE [-rules FILE ...] [JOB_SCRIPT ...]\n\n", command)
+		fmt.Fprintf(flags.Output(), "Checks the #DW directives of the job scripts, or stdin, against the DWDirectiveRules.\n\n")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if len(files) == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	rules, err := readRules(files)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 2
+	}
+
+	scripts := flags.Args()
+	if len(scripts) == 0 {
+		scripts = []string{"-"}
+	}
+
+	failed := checkRules(stdout, rules)
+	for _, script := range scripts {
+		directives, err := readDirectives(script, stdin, prefixes)
+		if err != nil {
+			fmt.Fprintf(stderr, "error: %s: %v\n", script, err)
+			return 2
+		}
+
+		if !lint(stdout, script, rules, directives) {
+			failed = true
+		}
+	}
+
+	if failed {
+		return 1
+	}
+
+	return 0
+}
+
+// readRules reads the DWDirectiveRule resources from YAML files that may hold several
+// documents. The driver label of a rule defaults to the name of its DWDirectiveRule, as
+// it does in the Workflow webhook.
+func readRules(files []string) ([]dwdparse.DWDirectiveRuleSpec, error) {
+	rules := []dwdparse.DWDirectiveRuleSpec{}
+
+	for _, file := range files {
+		fileRules, err := readRuleFile(file)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, fileRules...)
+	}
+
+	if len(rules) == 0 {
+		return nil, fmt.Errorf("no DWDirectiveRules found in %s", strings.Join(files, ", "))
+	}
+
+	return rules, nil
+}
+
+// readRuleFile reads the rules of the DWDirectiveRule resources in a single YAML file
+func readRuleFile(file string) ([]dwdparse.DWDirectiveRuleSpec, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	rules := []dwdparse.DWDirectiveRuleSpec{}
+	decoder := yaml.NewYAMLOrJSONDecoder(f, 4096)
+	for {
+		ruleSet := dwsv1alpha7.DWDirectiveRule{}
+		if err := decoder.Decode(&ruleSet); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+
+		// Skip empty documents and anything that isn't a rule. The spec of a
+		// DWDirectiveRule is the same in every API version.
+		if ruleSet.Kind != "DWDirectiveRule" {
+			continue
+		}
+
+		for _, rule := range ruleSet.Spec {
+			if rule.DriverLabel == "" {
+				rule.DriverLabel = ruleSet.Name
+			}
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules, nil
+}
+
+// readDirectives returns the directives of a job script. The script "-" is read from stdin.
+func readDirectives(script string, stdin io.Reader, prefixes []string) ([]dwdscript.Directive, error) {
+	r := stdin
+	if script != "-" {
+		f, err := os.Open(script)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	return dwdscript.Extract(r, dwdscript.Options{Prefixes: prefixes})
+}
+
+// checkRules reports the problems in the rules themselves. It returns true if any
+// were found.
+func checkRules(w io.Writer, rules []dwdparse.DWDirectiveRuleSpec) bool {
+	failed := false
+	for _, rule := range rules {
+		for _, problem := range dwdparse.CheckRule(rule) {
+			fmt.Fprintf(w, "rule %s (%s): %s: %s\n", rule.DriverLabel, rule.Command, problem.Field, problem.Message)
+			failed = true
+		}
+	}
+
+	return failed
+}
+
+// lint validates the directives of a job script and prints what each one matched. It
+// returns false if the directives aren't valid.
+func lint(w io.Writer, script string, rules []dwdparse.DWDirectiveRuleSpec, directives []dwdscript.Directive) bool {
+	name := script
+	if name == "-" {
+		name = "stdin"
+	}
+
+	if len(directives) == 0 {
+		fmt.Fprintf(w, "%s: no #DW directives\n", name)
+		return true
+	}
+
+	matches := make([][]dwdparse.DWDirectiveRuleSpec, len(directives))
+	err := dwdparse.NewRuleSet(rules).Validate(dwdscript.Texts(directives), func(index int, rule dwdparse.DWDirectiveRuleSpec) {
+		matches[index] = append(matches[index], rule)
+	})
+
+	// Gather the problems by directive
+	problems := map[int][]string{}
+	var validationErrs dwdparse.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		for _, verr := range validationErrs {
+			problems[verr.Index] = append(problems[verr.Index], fmt.Sprintf("%s: %s", verr.Reason, verr.Message))
+		}
+	} else if err != nil {
+		problems[-1] = append(problems[-1], err.Error())
+	}
+
+	for index, directive := range directives {
+		fmt.Fprintf(w, "%s:%d: %s\n", name, directive.Line, directive.Text)
+
+		for _, rule := range matches[index] {
+			fmt.Fprintf(w, "    rule: %s\n", rule.DriverLabel)
+			if rule.WatchStates != "" {
+				fmt.Fprintf(w, "    driver %s registers for: %s\n", rule.DriverLabel, strings.ReplaceAll(rule.WatchStates, ",", ", "))
+			}
+			if len(rule.DependsOn) > 0 {
+				fmt.Fprintf(w, "    driver %s waits for: %s\n", rule.DriverLabel, strings.Join(rule.DependsOn, ", "))
+			}
+
+			if defaults, err := dwdparse.Defaults(rule, directive.Text); err == nil && len(defaults) > 0 {
+				keys := make([]string, 0, len(defaults))
+				for k := range defaults {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+
+				for _, k := range keys {
+					fmt.Fprintf(w, "    default: %s=%s\n", k, defaults[k])
+				}
+			}
+		}
+
+		for _, problem := range problems[index] {
+			fmt.Fprintf(w, "    error: %s\n", problem)
+		}
+	}
+
+	for _, problem := range problems[-1] {
+		fmt.Fprintf(w, "%s: error: %s\n", name, problem)
+	}
+
+	if err != nil {
+		return false
+	}
+
+	fmt.Fprintf(w, "%s: %d directives OK\n", name, len(directives))
+	return true
+}
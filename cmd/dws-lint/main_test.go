@@ -0,0 +1,173 @@
+/*
+ * Copyright 2025 Hewlett Packard Enterprise Development LP
+ * Other additional copyright holders may be indicated within.
+ *
+ * The entirety of this work is licensed under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ *
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const rules = `apiVersion: dataworkflowservices.github.io/v1alpha7
+kind: DWDirectiveRule
+metadata:
+  name: jobdw-rules
+spec:
+- command: jobdw
+  watchStates: Proposal,Setup
+  ruleDefs:
+  - key: type
+    type: enum
+    enum: [xfs, lustre]
+    default: xfs
+  - key: capacity
+    type: size
+    isRequired: true
+    isValueRequired: true
+  - key: name
+    type: string
+    isRequired: true
+    isValueRequired: true
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: not-a-rule
+`
+
+const brokenRules = `apiVersion: dataworkflowservices.github.io/v1alpha7
+kind: DWDirectiveRule
+metadata:
+  name: broken-rules
+spec:
+- command: broken
+  ruleDefs:
+  - key: name
+    type: text
+`
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	rulesFile := write("rules.yaml", rules)
+	brokenRulesFile := write("broken.yaml", brokenRules)
+	emptyRulesFile := write("empty.yaml", "")
+	goodScript := write("good.sh", "#!/bin/bash\n#DW jobdw capacity=1TiB name=scratch\nsrun ./a.out\n")
+	badScript := write("bad.sh", "#!/bin/bash\n#DW jobdw type=gfs2 name=scratch\n")
+
+	tests := []struct {
+		name   string
+		args   []string
+		stdin  string
+		code   int
+		output []string
+	}{
+		{
+			name:   "valid script",
+			args:   []string{"-rules", rulesFile, goodScript},
+			code:   0,
+			output: []string{goodScript + ":2: #DW jobdw capacity=1TiB name=scratch", "rule: jobdw-rules", "registers for: Proposal, Setup", "default: type=xfs", "1 directives OK"},
+		},
+		{
+			name:   "valid stdin",
+			args:   []string{"-rules", rulesFile},
+			stdin:  "#DW jobdw type=lustre capacity=10GiB name=stdin\n",
+			code:   0,
+			output: []string{"stdin:1: #DW jobdw", "stdin: 1 directives OK"},
+		},
+		{
+			name:   "no directives",
+			args:   []string{"-rules", rulesFile, "-"},
+			stdin:  "#!/bin/bash\n",
+			code:   0,
+			output: []string{"stdin: no #DW directives"},
+		},
+		{
+			name:   "invalid directive",
+			args:   []string{"-rules", rulesFile, badScript},
+			code:   1,
+			output: []string{badScript + ":2:", "error: "},
+		},
+		{
+			name:   "valid and invalid scripts",
+			args:   []string{"-rules", rulesFile, goodScript, badScript},
+			code:   1,
+			output: []string{"1 directives OK", "error: "},
+		},
+		{
+			name:   "broken rule",
+			args:   []string{"-rules", rulesFile, "-rules", brokenRulesFile, goodScript},
+			code:   1,
+			output: []string{"rule broken-rules (broken): ruleDefs[0].type: unsupported rule type"},
+		},
+		{
+			name: "no rules flag",
+			args: []string{goodScript},
+			code: 2,
+		},
+		{
+			name: "unknown flag",
+			args: []string{"-rulez", rulesFile},
+			code: 2,
+		},
+		{
+			name: "missing rules file",
+			args: []string{"-rules", filepath.Join(dir, "missing.yaml"), goodScript},
+			code: 2,
+		},
+		{
+			name: "no rules in file",
+			args: []string{"-rules", emptyRulesFile, goodScript},
+			code: 2,
+		},
+		{
+			name: "missing script",
+			args: []string{"-rules", rulesFile, filepath.Join(dir, "missing.sh")},
+			code: 2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			code := run("dws-lint", test.args, strings.NewReader(test.stdin), stdout, stderr)
+			if code != test.code {
+				t.Errorf("expected exit code %d, got %d\nstdout:\n%s\nstderr:\n%s", test.code, code, stdout, stderr)
+			}
+
+			for _, expected := range test.output {
+				if !strings.Contains(stdout.String(), expected) {
+					t.Errorf("expected output to contain '%s'\nstdout:\n%s", expected, stdout)
+				}
+			}
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"flag"
 	"os"
 	"runtime"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -29,6 +30,7 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
@@ -61,12 +63,27 @@ func main() {
 	var enableLeaderElection bool
 	var probeAddr string
 	var mode string
+	var driverHeartbeatTimeout time.Duration
+	var driverHeartbeatErrorTimeout time.Duration
+	var cancelGracePeriod time.Duration
+	var nodeName string
+	var clientMountNamespace string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
-	flag.StringVar(&mode, "mode", "controller", "What mode to run in (controller, webhook)")
+	flag.StringVar(&mode, "mode", "controller", "What mode to run in (controller, webhook, client)")
+	flag.StringVar(&nodeName, "node-name", os.Getenv("NODE_NAME"),
+		"The client node whose ClientMounts are mounted in client mode. Defaults to $NODE_NAME.")
+	flag.StringVar(&clientMountNamespace, "clientmount-namespace", "",
+		"The namespace of the client node's ClientMounts in client mode. Defaults to the node name.")
+	flag.DurationVar(&driverHeartbeatTimeout, "driver-heartbeat-timeout", 0,
+		"Time a workflow driver may go without a heartbeat before it is reported as a TransientCondition. Zero disables the check.")
+	flag.DurationVar(&driverHeartbeatErrorTimeout, "driver-heartbeat-error-timeout", 0,
+		"Time a workflow driver may go without a heartbeat before it is reported as an Error. Zero disables the escalation.")
+	flag.DurationVar(&cancelGracePeriod, "workflow-cancel-grace-period", time.Minute,
+		"Time the drivers of a cancelled workflow are given to stop their work before the workflow is moved to Teardown.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -77,13 +94,34 @@ func main() {
 
 	setupLog.Info("GOMAXPROCS", "value", runtime.GOMAXPROCS(0))
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	options := ctrl.Options{
 		Scheme:                 scheme,
 		Metrics:                metricsserver.Options{BindAddress: metricsAddr},
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "a08857a2.dataworkflowservices.github.io",
-	})
+	}
+
+	// In client mode there is a daemon on every client node, and each one only needs the
+	// ClientMounts for its own node. Every daemon must run, so there's no leader election.
+	if mode == "client" {
+		if nodeName == "" {
+			setupLog.Error(nil, "client mode requires --node-name or $NODE_NAME")
+			os.Exit(1)
+		}
+
+		if clientMountNamespace == "" {
+			clientMountNamespace = nodeName
+		}
+
+		if enableLeaderElection {
+			setupLog.Info("leader election is disabled in client mode")
+		}
+		options.LeaderElection = false
+		options.Cache = cache.Options{DefaultNamespaces: map[string]cache.Config{clientMountNamespace: {}}}
+	}
+
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), options)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
@@ -92,33 +130,57 @@ func main() {
 	switch mode {
 	case "controller":
 		if err = (&controllers.WorkflowReconciler{
-			Client: mgr.GetClient(),
-			Log:    ctrl.Log.WithName("controllers").WithName("Workflow"),
-			Scheme: mgr.GetScheme(),
+			Client:                      mgr.GetClient(),
+			Log:                         ctrl.Log.WithName("controllers").WithName("Workflow"),
+			Scheme:                      mgr.GetScheme(),
+			Recorder:                    mgr.GetEventRecorderFor("dws-workflow"),
+			DriverHeartbeatTimeout:      driverHeartbeatTimeout,
+			DriverHeartbeatErrorTimeout: driverHeartbeatErrorTimeout,
+			CancelGracePeriod:           cancelGracePeriod,
 		}).SetupWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "Workflow")
 			os.Exit(1)
 		}
 
 		if err = (&controllers.SystemConfigurationReconciler{
-			Client: mgr.GetClient(),
-			Log:    ctrl.Log.WithName("controllers").WithName("SystemConfiguration"),
-			Scheme: mgr.GetScheme(),
+			Client:   mgr.GetClient(),
+			Log:      ctrl.Log.WithName("controllers").WithName("SystemConfiguration"),
+			Scheme:   mgr.GetScheme(),
+			Recorder: mgr.GetEventRecorderFor("dws-systemconfiguration"),
 		}).SetupWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "SystemConfiguration")
 			os.Exit(1)
 		}
 
 		if os.Getenv("ENVIRONMENT") == "kind" {
+			backends, _ := controllers.NewFakeClientMountBackends()
 			if err = (&controllers.ClientMountReconciler{
-				Client: mgr.GetClient(),
-				Log:    ctrl.Log.WithName("controllers").WithName("ClientMount"),
-				Scheme: mgr.GetScheme(),
+				Client:   mgr.GetClient(),
+				Log:      ctrl.Log.WithName("controllers").WithName("ClientMount"),
+				Scheme:   mgr.GetScheme(),
+				Recorder: mgr.GetEventRecorderFor("dws-clientmount"),
+				Backends: backends,
 			}).SetupWithManager(mgr); err != nil {
 				setupLog.Error(err, "unable to create controller", "controller", "Workflow")
 				os.Exit(1)
 			}
 		}
+	case "client":
+		// The objects behind reference devices are owned by other services, and the
+		// client has no backends for them
+		setupLog.Info("reference devices are not supported in client mode; ClientMounts that use them will report an error")
+
+		if err = (&controllers.ClientMountReconciler{
+			Client:   mgr.GetClient(),
+			Log:      ctrl.Log.WithName("controllers").WithName("ClientMount"),
+			Scheme:   mgr.GetScheme(),
+			Recorder: mgr.GetEventRecorderFor("dws-clientmount"),
+			NodeName: nodeName,
+			Backends: controllers.NewClientMountBackends(nil),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ClientMount")
+			os.Exit(1)
+		}
 	case "webhook":
 		if err = (&dwsv1alpha7.ClientMount{}).SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "ClientMount")
@@ -0,0 +1,211 @@
+/*
+ * Copyright 2025 Hewlett Packard Enterprise Development LP
+ * Other additional copyright holders may be indicated within.
+ *
+ * The entirety of this work is licensed under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ *
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	dwsv1alpha7 "github.com/DataWorkflowServices/dws/api/v1alpha7"
+)
+
+// ClientMountBackend mounts and unmounts the devices described by a ClientMount on the
+// node the ClientMount reconciler is running on. The reconciler calls Mount or Unmount
+// again until it succeeds, so both must succeed when the mount is already in the
+// requested state.
+type ClientMountBackend interface {
+	Mount(ctx context.Context, clientMount *dwsv1alpha7.ClientMount, mount *dwsv1alpha7.ClientMountInfo) error
+	Unmount(ctx context.Context, clientMount *dwsv1alpha7.ClientMount, mount *dwsv1alpha7.ClientMountInfo) error
+}
+
+// ClientMountBackends maps each device type to the backend that handles it
+type ClientMountBackends map[dwsv1alpha7.ClientMountDeviceType]ClientMountBackend
+
+// NewClientMountBackends returns the backends that mount the lustre, lvm, nfs, generic,
+// and bind devices with the mount commands of the node. Reference devices are passed to
+// the backend registered for the kind of the referenced object. Without any kinds there
+// is no backend for reference devices, and the reconciler reports them as unsupported.
+func NewClientMountBackends(referenceKinds map[string]ClientMountBackend) ClientMountBackends {
+	mounter := &Mounter{}
+
+	backends := ClientMountBackends{
+		dwsv1alpha7.ClientMountDeviceTypeLustre:  &LustreBackend{Mounter: mounter},
+		dwsv1alpha7.ClientMountDeviceTypeLVM:     &LVMBackend{Mounter: mounter},
+		dwsv1alpha7.ClientMountDeviceTypeNFS:     &NFSBackend{Mounter: mounter},
+		dwsv1alpha7.ClientMountDeviceTypeGeneric: &GenericBackend{Mounter: mounter},
+		dwsv1alpha7.ClientMountDeviceTypeBind:    &BindBackend{Mounter: mounter},
+	}
+
+	if len(referenceKinds) != 0 {
+		backends[dwsv1alpha7.ClientMountDeviceTypeReference] = &ReferenceBackend{Kinds: referenceKinds}
+	}
+
+	return backends
+}
+
+// ReferenceBackend handles the reference devices. The ClientMount only names the object
+// that describes the device, so the work is passed to the backend registered for the
+// kind of that object.
+type ReferenceBackend struct {
+	Kinds map[string]ClientMountBackend
+}
+
+// Mount mounts a reference device with the backend for its kind
+func (b *ReferenceBackend) Mount(ctx context.Context, clientMount *dwsv1alpha7.ClientMount, mount *dwsv1alpha7.ClientMountInfo) error {
+	backend, err := b.backend(mount)
+	if err != nil {
+		return err
+	}
+
+	return backend.Mount(ctx, clientMount, mount)
+}
+
+// Unmount unmounts a reference device with the backend for its kind
+func (b *ReferenceBackend) Unmount(ctx context.Context, clientMount *dwsv1alpha7.ClientMount, mount *dwsv1alpha7.ClientMountInfo) error {
+	backend, err := b.backend(mount)
+	if err != nil {
+		return err
+	}
+
+	return backend.Unmount(ctx, clientMount, mount)
+}
+
+func (b *ReferenceBackend) backend(mount *dwsv1alpha7.ClientMountInfo) (ClientMountBackend, error) {
+	if mount.Device.DeviceReference == nil {
+		return nil, dwsv1alpha7.NewResourceError("mount %s has no device reference", mount.MountPath).WithFatal()
+	}
+
+	kind := mount.Device.DeviceReference.ObjectReference.Kind
+	backend, found := b.Kinds[kind]
+	if !found {
+		return nil, dwsv1alpha7.NewResourceError("no mount backend for device references of kind '%s'", kind).WithFatal()
+	}
+
+	return backend, nil
+}
+
+// FakeClientMountBackend pretends to mount and unmount devices. It remembers which mount
+// paths are mounted on which node, so tests can check what the reconciler did. If Err is
+// set, it is returned instead. A mount path that is marked busy can only be unmounted
+// with the Lazy or Force unmount policies.
+type FakeClientMountBackend struct {
+	sync.Mutex
+	mounted    map[string]bool
+	busy       map[string]bool
+	operations map[string][]string
+
+	Err error
+}
+
+// NewFakeClientMountBackends returns backends for each device type that share one
+// FakeClientMountBackend
+func NewFakeClientMountBackends() (ClientMountBackends, *FakeClientMountBackend) {
+	fake := &FakeClientMountBackend{}
+
+	return ClientMountBackends{
+		dwsv1alpha7.ClientMountDeviceTypeLustre:    fake,
+		dwsv1alpha7.ClientMountDeviceTypeLVM:       fake,
+		dwsv1alpha7.ClientMountDeviceTypeReference: fake,
+		dwsv1alpha7.ClientMountDeviceTypeNFS:       fake,
+		dwsv1alpha7.ClientMountDeviceTypeGeneric:   fake,
+		dwsv1alpha7.ClientMountDeviceTypeBind:      fake,
+	}, fake
+}
+
+// Mount records the mount path as mounted
+func (f *FakeClientMountBackend) Mount(ctx context.Context, clientMount *dwsv1alpha7.ClientMount, mount *dwsv1alpha7.ClientMountInfo) error {
+	return f.set(clientMount.Spec.Node, mount.MountPath, true)
+}
+
+// Unmount records the mount path as unmounted
+func (f *FakeClientMountBackend) Unmount(ctx context.Context, clientMount *dwsv1alpha7.ClientMount, mount *dwsv1alpha7.ClientMountInfo) error {
+	key := fakeMountKey(clientMount.Spec.Node, mount.MountPath)
+
+	f.Lock()
+	busy := f.mounted[key] && f.busy[key]
+	f.Unlock()
+
+	if busy && unmountPolicy(clientMount) == dwsv1alpha7.ClientMountUnmountPolicyNormal {
+		return fmt.Errorf("%w: %s", ErrMountBusy, mount.MountPath)
+	}
+
+	return f.set(clientMount.Spec.Node, mount.MountPath, false)
+}
+
+// SetBusy marks a mount path on the node as having files in use, or not
+func (f *FakeClientMountBackend) SetBusy(node string, path string, busy bool) {
+	f.Lock()
+	defer f.Unlock()
+
+	if f.busy == nil {
+		f.busy = map[string]bool{}
+	}
+
+	f.busy[fakeMountKey(node, path)] = busy
+}
+
+// IsMounted returns true if the mount path is mounted on the node
+func (f *FakeClientMountBackend) IsMounted(node string, path string) bool {
+	f.Lock()
+	defer f.Unlock()
+
+	return f.mounted[fakeMountKey(node, path)]
+}
+
+func (f *FakeClientMountBackend) set(node string, path string, mounted bool) error {
+	f.Lock()
+	defer f.Unlock()
+
+	if f.Err != nil {
+		return f.Err
+	}
+
+	if f.mounted == nil {
+		f.mounted = map[string]bool{}
+	}
+
+	f.mounted[fakeMountKey(node, path)] = mounted
+
+	if f.operations == nil {
+		f.operations = map[string][]string{}
+	}
+
+	operation := "unmount " + path
+	if mounted {
+		operation = "mount " + path
+	}
+	f.operations[node] = append(f.operations[node], operation)
+
+	return nil
+}
+
+// Operations returns the mounts and unmounts done on the node, in the order they were done
+func (f *FakeClientMountBackend) Operations(node string) []string {
+	f.Lock()
+	defer f.Unlock()
+
+	return slices.Clone(f.operations[node])
+}
+
+func fakeMountKey(node string, path string) string {
+	return fmt.Sprintf("%s:%s", node, path)
+}
@@ -21,25 +21,43 @@ package controllers
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	dwsv1alpha7 "github.com/DataWorkflowServices/dws/api/v1alpha7"
+	"github.com/DataWorkflowServices/dws/internal/controller/metrics"
 	"github.com/DataWorkflowServices/dws/utils/updater"
 )
 
 // ClientMountReconciler reconciles a ClientMount object
 type ClientMountReconciler struct {
 	client.Client
-	Log    logr.Logger
-	Scheme *runtime.Scheme
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// NodeName is the name of the client node the reconciler does the mounts for. Only
+	// the ClientMounts with this spec.node are reconciled. When it is empty, all of the
+	// ClientMounts in the compute namespaces are reconciled.
+	NodeName string
+
+	// Backends mount and unmount the devices of each device type
+	Backends ClientMountBackends
 }
 
 const (
@@ -50,10 +68,14 @@ const (
 //+kubebuilder:rbac:groups=dataworkflowservices.github.io,resources=clientmounts,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=dataworkflowservices.github.io,resources=clientmounts/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=dataworkflowservices.github.io,resources=clientmounts/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 func (r *ClientMountReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, err error) {
+	metrics.DwsReconcilesTotal.Inc()
+	defer func(start time.Time) { metrics.ObserveReconcile("clientmount", start, err) }(time.Now())
+
 	clientMount := &dwsv1alpha7.ClientMount{}
 	if err := r.Get(ctx, req.NamespacedName, clientMount); err != nil {
 		// ignore not-found errors, since they can't be fixed by an immediate
@@ -66,6 +88,7 @@ func (r *ClientMountReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	// in clientMount.Status{} change
 	statusUpdater := updater.NewStatusUpdater[*dwsv1alpha7.ClientMountStatus](clientMount)
 	defer func() { err = statusUpdater.CloseWithStatusUpdate(ctx, r.Client.Status(), err) }()
+	defer func() { setClientMountConditions(clientMount) }()
 	defer func() { clientMount.Status.SetResourceError(err) }()
 
 	// Handle cleanup if the resource is being deleted
@@ -74,6 +97,11 @@ func (r *ClientMountReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 			return ctrl.Result{}, nil
 		}
 
+		// Leave nothing mounted behind
+		if err := r.unmountAll(ctx, clientMount); err != nil {
+			return ctrl.Result{}, err
+		}
+
 		controllerutil.RemoveFinalizer(clientMount, finalizerClientMount)
 		if err := r.Update(ctx, clientMount); err != nil {
 			return ctrl.Result{}, err
@@ -89,10 +117,17 @@ func (r *ClientMountReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 	// Initialize the status section if the desired state doesn't match the status state
 	if clientMount.Status.Mounts[0].State != clientMount.Spec.DesiredState {
-		for i := 0; i < len(clientMount.Status.Mounts); i++ {
-			clientMount.Status.Mounts[i].State = clientMount.Spec.DesiredState
-			clientMount.Status.Mounts[i].Ready = false
+		now := metav1.NowMicro()
+		for i := range clientMount.Status.Mounts {
+			clientMount.Status.Mounts[i] = dwsv1alpha7.ClientMountInfoStatus{
+				State:              clientMount.Spec.DesiredState,
+				Ready:              false,
+				Message:            fmt.Sprintf("waiting to be %s", clientMount.Spec.DesiredState),
+				LastTransitionTime: &now,
+			}
 		}
+		clientMount.Status.AllReady = false
+		r.Recorder.Eventf(clientMount, corev1.EventTypeNormal, EventReasonStateChange, "ClientMount transitioning to state %s", clientMount.Spec.DesiredState)
 
 		return ctrl.Result{}, nil
 	}
@@ -107,14 +142,205 @@ func (r *ClientMountReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, nil
 	}
 
-	for i := range clientMount.Spec.Mounts {
+	// Stop at the first mount that fails. The mounts after it may be nested inside it.
+	for _, i := range mountOrder(clientMount, clientMount.Spec.DesiredState) {
+		if clientMount.Status.Mounts[i].Ready {
+			continue
+		}
+
+		if err := r.changeMount(ctx, clientMount, i, clientMount.Spec.DesiredState); err != nil {
+			break
+		}
+
+		now := metav1.NowMicro()
 		clientMount.Status.Mounts[i].Ready = true
+		clientMount.Status.Mounts[i].Message = ""
+		clientMount.Status.Mounts[i].LastTransitionTime = &now
 	}
-	clientMount.Status.AllReady = true
 
-	clientMount.Status.Error = nil
+	allReady := true
+	for _, mountStatus := range clientMount.Status.Mounts {
+		allReady = allReady && mountStatus.Ready
+	}
+
+	if allReady && !clientMount.Status.AllReady {
+		r.Recorder.Eventf(clientMount, corev1.EventTypeNormal, EventReasonReady, "ClientMount reached state %s", clientMount.Spec.DesiredState)
+	}
+	clientMount.Status.AllReady = allReady
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{}, mountsError(clientMount)
+}
+
+// mountOrder returns the indexes of the mounts in the order they are changed. A mount
+// that depends on another mount is mounted after it, and the unmounts are done in the
+// reverse of the mount order, so the deepest mounts are unmounted first. Of the mounts
+// whose dependencies are already mounted, the one listed first goes next.
+func mountOrder(clientMount *dwsv1alpha7.ClientMount, state dwsv1alpha7.ClientMountState) []int {
+	mounts := clientMount.Spec.Mounts
+
+	order := []int{}
+	done := make([]bool, len(mounts))
+	for len(order) < len(mounts) {
+		next := -1
+		for i := range mounts {
+			if done[i] {
+				continue
+			}
+
+			ready := true
+			for j := range mounts {
+				if !done[j] && j != i && mountDependsOn(&mounts[i], &mounts[j]) {
+					ready = false
+					break
+				}
+			}
+
+			if ready {
+				next = i
+				break
+			}
+		}
+
+		// The dependencies form a cycle. Take the first of the remaining mounts.
+		if next == -1 {
+			next = slices.Index(done, false)
+		}
+
+		done[next] = true
+		order = append(order, next)
+	}
+
+	if state == dwsv1alpha7.ClientMountStateUnmounted {
+		slices.Reverse(order)
+	}
+
+	return order
+}
+
+// mountDependsOn returns true if a mount has to be mounted after another mount because
+// its mount path, or the path it bind mounts, is inside the other mount
+func mountDependsOn(mount *dwsv1alpha7.ClientMountInfo, other *dwsv1alpha7.ClientMountInfo) bool {
+	if isSubPath(mount.MountPath, other.MountPath) {
+		return true
+	}
+
+	return mount.Device.Bind != nil && (mount.Device.Bind.SourcePath == other.MountPath || isSubPath(mount.Device.Bind.SourcePath, other.MountPath))
+}
+
+// isSubPath returns true if the path is below the parent directory
+func isSubPath(path string, parent string) bool {
+	rel, err := filepath.Rel(parent, path)
+	return err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, "../")
+}
+
+// changeMount mounts or unmounts one of the mounts. The outcome is recorded in the
+// mount's error and message.
+func (r *ClientMountReconciler) changeMount(ctx context.Context, clientMount *dwsv1alpha7.ClientMount, index int, state dwsv1alpha7.ClientMountState) error {
+	mount := &clientMount.Spec.Mounts[index]
+	mountStatus := &clientMount.Status.Mounts[index]
+
+	err := func() error {
+		backend, found := r.Backends[mount.Device.Type]
+		if !found {
+			if mount.Device.Type == dwsv1alpha7.ClientMountDeviceTypeReference && mount.Device.DeviceReference != nil {
+				ref := mount.Device.DeviceReference.ObjectReference
+				return dwsv1alpha7.NewResourceError("reference devices are not supported on this node: unable to mount %s %s/%s", ref.Kind, ref.Namespace, ref.Name).WithFatal()
+			}
+			return dwsv1alpha7.NewResourceError("no mount backend for device type '%s'", mount.Device.Type).WithFatal()
+		}
+
+		if state == dwsv1alpha7.ClientMountStateMounted {
+			return backend.Mount(ctx, clientMount, mount)
+		}
+
+		return backend.Unmount(ctx, clientMount, mount)
+	}()
+
+	mountStatus.Busy = errors.Is(err, ErrMountBusy)
+	if err == nil {
+		mountStatus.Error = nil
+		return nil
+	}
+
+	mountErr := dwsv1alpha7.NewResourceError("unable to change %s on node %s to %s", mount.MountPath, clientMount.Spec.Node, state).WithError(err)
+	mountErr.WithUserMessage("%s could not be %s on node %s", mount.MountPath, state, clientMount.Spec.Node)
+	mountStatus.Error = mountErr
+	mountStatus.Message = mountErr.Error()
+	if mountStatus.Busy {
+		mountStatus.Message = fmt.Sprintf("waiting for the files in use on %s to be closed", mount.MountPath)
+	}
+
+	r.Recorder.Eventf(clientMount, corev1.EventTypeWarning, EventReasonMountFailed, "Mount %s could not be %s: %v", mount.MountPath, state, err)
+
+	return mountErr
+}
+
+// severityRank orders the error severities from least to most severe
+var severityRank = map[dwsv1alpha7.ResourceErrorSeverity]int{
+	dwsv1alpha7.SeverityMinor: 0,
+	dwsv1alpha7.SeverityMajor: 1,
+	dwsv1alpha7.SeverityFatal: 2,
+}
+
+// mountsError derives the error of the ClientMount from the errors of its mounts. The
+// error takes the severity of the most severe mount error, and lists each of the mounts
+// that failed.
+func mountsError(clientMount *dwsv1alpha7.ClientMount) error {
+	var worst *dwsv1alpha7.ResourceErrorInfo
+	failed := []string{}
+	for i, mountStatus := range clientMount.Status.Mounts {
+		if mountStatus.Error == nil {
+			continue
+		}
+
+		failed = append(failed, clientMount.Spec.Mounts[i].MountPath)
+		if worst == nil || severityRank[mountStatus.Error.Severity] > severityRank[worst.Severity] {
+			worst = mountStatus.Error
+		}
+	}
+
+	if worst == nil {
+		return nil
+	}
+
+	return dwsv1alpha7.NewResourceError("mounts failed on node %s: %s", clientMount.Spec.Node, strings.Join(failed, ", ")).WithError(worst)
+}
+
+// unmountAll unmounts the mounts of a ClientMount that is being deleted, in the reverse
+// of the order they were mounted
+func (r *ClientMountReconciler) unmountAll(ctx context.Context, clientMount *dwsv1alpha7.ClientMount) error {
+	mounted := false
+	for _, mountStatus := range clientMount.Status.Mounts {
+		if mountStatus.State == dwsv1alpha7.ClientMountStateMounted {
+			mounted = true
+		}
+	}
+
+	if !mounted {
+		return nil
+	}
+
+	for _, i := range mountOrder(clientMount, dwsv1alpha7.ClientMountStateUnmounted) {
+		if err := r.changeMount(ctx, clientMount, i, dwsv1alpha7.ClientMountStateUnmounted); err != nil {
+			return mountsError(clientMount)
+		}
+	}
+
+	return nil
+}
+
+// setClientMountConditions derives the ClientMount's conditions from its status
+func setClientMountConditions(clientMount *dwsv1alpha7.ClientMount) {
+	conditions := &clientMount.Status.Conditions
+	generation := clientMount.GetGeneration()
+
+	if clientMount.Status.AllReady {
+		dwsv1alpha7.SetCondition(conditions, dwsv1alpha7.ConditionTypeReady, true, dwsv1alpha7.ConditionReasonReady, fmt.Sprintf("all mounts are %s", clientMount.Spec.DesiredState), generation)
+	} else {
+		dwsv1alpha7.SetCondition(conditions, dwsv1alpha7.ConditionTypeReady, false, dwsv1alpha7.ConditionReasonNotReady, fmt.Sprintf("waiting for mounts to be %s", clientMount.Spec.DesiredState), generation)
+	}
+
+	clientMount.Status.SetErrorConditions(conditions, generation)
 }
 
 func filterByComputeNamespacePrefix() predicate.Predicate {
@@ -123,10 +349,23 @@ func filterByComputeNamespacePrefix() predicate.Predicate {
 	})
 }
 
+// filterByNode keeps only the ClientMounts for the named client node
+func filterByNode(nodeName string) predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(object client.Object) bool {
+		clientMount, ok := object.(*dwsv1alpha7.ClientMount)
+		return ok && clientMount.Spec.Node == nodeName
+	})
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *ClientMountReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	filter := filterByComputeNamespacePrefix()
+	if r.NodeName != "" {
+		filter = filterByNode(r.NodeName)
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&dwsv1alpha7.ClientMount{}).
-		WithEventFilter(filterByComputeNamespacePrefix()).
+		WithEventFilter(filter).
 		Complete(r)
 }
@@ -0,0 +1,308 @@
+/*
+ * Copyright 2025 Hewlett Packard Enterprise Development LP
+ * Other additional copyright holders may be indicated within.
+ *
+ * The entirety of this work is licensed under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ *
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	dwsv1alpha7 "github.com/DataWorkflowServices/dws/api/v1alpha7"
+)
+
+// ErrMountBusy is returned by a backend when a mount can't be unmounted because files
+// on it are in use
+var ErrMountBusy = errors.New("mount is busy")
+
+// CommandRunner runs a command and returns its combined output
+type CommandRunner func(ctx context.Context, name string, args ...string) ([]byte, error)
+
+// Mounter runs the mount commands for the lustre, lvm, nfs, generic, and bind backends
+type Mounter struct {
+	// Run runs the commands. The commands are run with os/exec when it is nil.
+	Run CommandRunner
+
+	// MountsFile lists the file systems that are mounted, in the format of
+	// /proc/self/mounts. /proc/self/mounts is used when it is empty.
+	MountsFile string
+}
+
+func (m *Mounter) run(ctx context.Context, name string, args ...string) error {
+	run := m.Run
+	if run == nil {
+		run = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+			return exec.CommandContext(ctx, name, args...).CombinedOutput()
+		}
+	}
+
+	output, err := run(ctx, name, args...)
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %w: %s", name, strings.Join(args, " "), err, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+// IsMounted returns true if a file system is mounted on the path
+func (m *Mounter) IsMounted(path string) (bool, error) {
+	mountsFile := m.MountsFile
+	if mountsFile == "" {
+		mountsFile = "/proc/self/mounts"
+	}
+
+	file, err := os.Open(mountsFile)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	// The mounts file escapes the whitespace in the mount point as octal
+	escaped := strings.NewReplacer(" ", `\040`, "\t", `\011`, "\n", `\012`, `\`, `\134`).Replace(path)
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) > 1 && fields[1] == escaped {
+			return true, nil
+		}
+	}
+
+	return false, scanner.Err()
+}
+
+// Mount mounts the source on the mount path, creating the mount target first. Nothing is
+// done if something is already mounted there. The file system type is left for mount to
+// work out when it is empty.
+func (m *Mounter) Mount(ctx context.Context, source string, fsType string, options string, mount *dwsv1alpha7.ClientMountInfo) error {
+	mounted, err := m.IsMounted(mount.MountPath)
+	if err != nil || mounted {
+		return err
+	}
+
+	if err := createMountTarget(mount); err != nil {
+		return err
+	}
+
+	args := []string{}
+	if fsType != "" {
+		args = append(args, "-t", fsType)
+	}
+	if options != "" {
+		args = append(args, "-o", options)
+	}
+	args = append(args, source, mount.MountPath)
+
+	if err := m.run(ctx, "mount", args...); err != nil {
+		return err
+	}
+
+	if mount.SetPermissions {
+		if err := os.Chown(mount.MountPath, int(mount.UserID), int(mount.GroupID)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Unmount unmounts the mount path if something is mounted there. ErrMountBusy is
+// returned when the unmount fails because files on the mount are in use.
+func (m *Mounter) Unmount(ctx context.Context, mount *dwsv1alpha7.ClientMountInfo, policy dwsv1alpha7.ClientMountUnmountPolicy) error {
+	mounted, err := m.IsMounted(mount.MountPath)
+	if err != nil || !mounted {
+		return err
+	}
+
+	args := []string{}
+	switch policy {
+	case dwsv1alpha7.ClientMountUnmountPolicyLazy:
+		args = append(args, "--lazy")
+	case dwsv1alpha7.ClientMountUnmountPolicyForce:
+		args = append(args, "--force")
+	}
+	args = append(args, mount.MountPath)
+
+	if err := m.run(ctx, "umount", args...); err != nil {
+		if strings.Contains(err.Error(), "busy") {
+			return fmt.Errorf("%w: %w", ErrMountBusy, err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// createMountTarget creates the directory or file the device is mounted on
+func createMountTarget(mount *dwsv1alpha7.ClientMountInfo) error {
+	if mount.TargetType != "file" {
+		return os.MkdirAll(mount.MountPath, 0755)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(mount.MountPath), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(mount.MountPath, os.O_CREATE|os.O_RDONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	return file.Close()
+}
+
+// LustreBackend mounts lustre file systems
+type LustreBackend struct {
+	*Mounter
+}
+
+// Mount mounts the lustre file system
+func (b *LustreBackend) Mount(ctx context.Context, clientMount *dwsv1alpha7.ClientMount, mount *dwsv1alpha7.ClientMountInfo) error {
+	lustre := mount.Device.Lustre
+	if lustre == nil {
+		return dwsv1alpha7.NewResourceError("mount %s has no lustre device", mount.MountPath).WithFatal()
+	}
+
+	return b.Mounter.Mount(ctx, fmt.Sprintf("%s:/%s", lustre.MgsAddresses, lustre.FileSystemName), "lustre", mount.Options, mount)
+}
+
+// Unmount unmounts the lustre file system
+func (b *LustreBackend) Unmount(ctx context.Context, clientMount *dwsv1alpha7.ClientMount, mount *dwsv1alpha7.ClientMountInfo) error {
+	return b.Mounter.Unmount(ctx, mount, unmountPolicy(clientMount))
+}
+
+// LVMBackend activates the volume group of an LVM logical volume and mounts the file
+// system on the logical volume. A mount of type "none" only activates the volume group.
+type LVMBackend struct {
+	*Mounter
+}
+
+// Mount activates the volume group and mounts the logical volume
+func (b *LVMBackend) Mount(ctx context.Context, clientMount *dwsv1alpha7.ClientMount, mount *dwsv1alpha7.ClientMountInfo) error {
+	lvm := mount.Device.LVM
+	if lvm == nil {
+		return dwsv1alpha7.NewResourceError("mount %s has no lvm device", mount.MountPath).WithFatal()
+	}
+
+	if err := b.run(ctx, "vgchange", "--activate", "y", lvm.VolumeGroup); err != nil {
+		return err
+	}
+
+	if mount.Type == "none" {
+		return nil
+	}
+
+	return b.Mounter.Mount(ctx, filepath.Join("/dev", lvm.VolumeGroup, lvm.LogicalVolume), mount.Type, mount.Options, mount)
+}
+
+// Unmount unmounts the logical volume and deactivates the volume group
+func (b *LVMBackend) Unmount(ctx context.Context, clientMount *dwsv1alpha7.ClientMount, mount *dwsv1alpha7.ClientMountInfo) error {
+	lvm := mount.Device.LVM
+	if lvm == nil {
+		return dwsv1alpha7.NewResourceError("mount %s has no lvm device", mount.MountPath).WithFatal()
+	}
+
+	if mount.Type != "none" {
+		if err := b.Mounter.Unmount(ctx, mount, unmountPolicy(clientMount)); err != nil {
+			return err
+		}
+	}
+
+	return b.run(ctx, "vgchange", "--activate", "n", lvm.VolumeGroup)
+}
+
+// NFSBackend mounts NFS exports
+type NFSBackend struct {
+	*Mounter
+}
+
+// Mount mounts the NFS export
+func (b *NFSBackend) Mount(ctx context.Context, clientMount *dwsv1alpha7.ClientMount, mount *dwsv1alpha7.ClientMountInfo) error {
+	nfs := mount.Device.NFS
+	if nfs == nil {
+		return dwsv1alpha7.NewResourceError("mount %s has no nfs device", mount.MountPath).WithFatal()
+	}
+
+	return b.Mounter.Mount(ctx, fmt.Sprintf("%s:%s", nfs.Server, nfs.Export), "nfs", mount.Options, mount)
+}
+
+// Unmount unmounts the NFS export
+func (b *NFSBackend) Unmount(ctx context.Context, clientMount *dwsv1alpha7.ClientMount, mount *dwsv1alpha7.ClientMountInfo) error {
+	return b.Mounter.Unmount(ctx, mount, unmountPolicy(clientMount))
+}
+
+// GenericBackend mounts a source with the file system type given by the device. It is
+// for file systems, such as GPFS, that have no device type of their own.
+type GenericBackend struct {
+	*Mounter
+}
+
+// Mount mounts the source
+func (b *GenericBackend) Mount(ctx context.Context, clientMount *dwsv1alpha7.ClientMount, mount *dwsv1alpha7.ClientMountInfo) error {
+	generic := mount.Device.Generic
+	if generic == nil {
+		return dwsv1alpha7.NewResourceError("mount %s has no generic device", mount.MountPath).WithFatal()
+	}
+
+	return b.Mounter.Mount(ctx, generic.Source, generic.FileSystemType, mount.Options, mount)
+}
+
+// Unmount unmounts the source
+func (b *GenericBackend) Unmount(ctx context.Context, clientMount *dwsv1alpha7.ClientMount, mount *dwsv1alpha7.ClientMountInfo) error {
+	return b.Mounter.Unmount(ctx, mount, unmountPolicy(clientMount))
+}
+
+// BindBackend bind mounts a directory or file of the client node
+type BindBackend struct {
+	*Mounter
+}
+
+// Mount bind mounts the source path on the mount path
+func (b *BindBackend) Mount(ctx context.Context, clientMount *dwsv1alpha7.ClientMount, mount *dwsv1alpha7.ClientMountInfo) error {
+	bind := mount.Device.Bind
+	if bind == nil {
+		return dwsv1alpha7.NewResourceError("mount %s has no bind device", mount.MountPath).WithFatal()
+	}
+
+	options := "bind"
+	if mount.Options != "" {
+		options += "," + mount.Options
+	}
+
+	return b.Mounter.Mount(ctx, bind.SourcePath, "", options, mount)
+}
+
+// Unmount unmounts the bind mount
+func (b *BindBackend) Unmount(ctx context.Context, clientMount *dwsv1alpha7.ClientMount, mount *dwsv1alpha7.ClientMountInfo) error {
+	return b.Mounter.Unmount(ctx, mount, unmountPolicy(clientMount))
+}
+
+// unmountPolicy returns the unmount policy of the ClientMount
+func unmountPolicy(clientMount *dwsv1alpha7.ClientMount) dwsv1alpha7.ClientMountUnmountPolicy {
+	if clientMount == nil || clientMount.Spec.UnmountPolicy == "" {
+		return dwsv1alpha7.ClientMountUnmountPolicyNormal
+	}
+
+	return clientMount.Spec.UnmountPolicy
+}
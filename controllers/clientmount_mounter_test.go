@@ -0,0 +1,226 @@
+/*
+ * Copyright 2025 Hewlett Packard Enterprise Development LP
+ * Other additional copyright holders may be indicated within.
+ *
+ * The entirety of this work is licensed under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ *
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	dwsv1alpha7 "github.com/DataWorkflowServices/dws/api/v1alpha7"
+)
+
+// testMounter is a Mounter that records the commands it would run. The mounts file starts
+// out without any of the test's mounts.
+type testMounter struct {
+	*Mounter
+	dir      string
+	commands []string
+}
+
+func newTestMounter(t *testing.T) *testMounter {
+	m := &testMounter{dir: t.TempDir()}
+	m.Mounter = &Mounter{
+		Run: func(ctx context.Context, name string, args ...string) ([]byte, error) {
+			m.commands = append(m.commands, strings.Join(append([]string{name}, args...), " "))
+			return nil, nil
+		},
+		MountsFile: filepath.Join(m.dir, "mounts"),
+	}
+	m.setMounts(t, "proc /proc proc rw 0 0\n")
+
+	return m
+}
+
+func (m *testMounter) setMounts(t *testing.T, mounts string) {
+	if err := os.WriteFile(m.MountsFile, []byte(mounts), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (m *testMounter) expectCommands(t *testing.T, expected ...string) {
+	t.Helper()
+	if !reflect.DeepEqual(m.commands, expected) {
+		t.Errorf("expected commands %q, got %q", expected, m.commands)
+	}
+	m.commands = nil
+}
+
+func TestLustreBackend(t *testing.T) {
+	m := newTestMounter(t)
+	mount := &dwsv1alpha7.ClientMountInfo{
+		MountPath: filepath.Join(m.dir, "lustre"),
+		Options:   "noatime",
+		Device: dwsv1alpha7.ClientMountDevice{
+			Type:   dwsv1alpha7.ClientMountDeviceTypeLustre,
+			Lustre: &dwsv1alpha7.ClientMountDeviceLustre{FileSystemName: "lus", MgsAddresses: "10.0.0.1@tcp"},
+		},
+		TargetType: "directory",
+	}
+
+	backend := &LustreBackend{Mounter: m.Mounter}
+	if err := backend.Mount(context.TODO(), nil, mount); err != nil {
+		t.Fatalf("unexpected mount error: %v", err)
+	}
+	m.expectCommands(t, "mount -t lustre -o noatime 10.0.0.1@tcp:/lus "+mount.MountPath)
+
+	if info, err := os.Stat(mount.MountPath); err != nil || !info.IsDir() {
+		t.Errorf("expected the mount target to be a directory: %v", err)
+	}
+
+	// Nothing is unmounted when the mounts file doesn't list the mount path
+	if err := backend.Unmount(context.TODO(), nil, mount); err != nil {
+		t.Fatalf("unexpected unmount error: %v", err)
+	}
+	m.expectCommands(t)
+
+	// A busy mount is reported as busy, and the unmount policy picks the umount flags
+	m.setMounts(t, "10.0.0.1@tcp:/lus "+mount.MountPath+" lustre rw 0 0\n")
+	m.Run = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		m.commands = append(m.commands, strings.Join(append([]string{name}, args...), " "))
+		return []byte("umount: target is busy."), errors.New("exit status 32")
+	}
+	if err := backend.Unmount(context.TODO(), nil, mount); !errors.Is(err, ErrMountBusy) {
+		t.Errorf("expected a busy mount, got %v", err)
+	}
+
+	clientMount := &dwsv1alpha7.ClientMount{Spec: dwsv1alpha7.ClientMountSpec{UnmountPolicy: dwsv1alpha7.ClientMountUnmountPolicyForce}}
+	if err := backend.Unmount(context.TODO(), clientMount, mount); err == nil {
+		t.Errorf("expected the forced unmount to fail")
+	}
+	m.expectCommands(t, "umount "+mount.MountPath, "umount --force "+mount.MountPath)
+}
+
+func TestLVMBackend(t *testing.T) {
+	m := newTestMounter(t)
+	mount := &dwsv1alpha7.ClientMountInfo{
+		MountPath: filepath.Join(m.dir, "xfs"),
+		Device: dwsv1alpha7.ClientMountDevice{
+			Type: dwsv1alpha7.ClientMountDeviceTypeLVM,
+			LVM:  &dwsv1alpha7.ClientMountDeviceLVM{VolumeGroup: "vg", LogicalVolume: "lv"},
+		},
+		Type:       "xfs",
+		TargetType: "file",
+	}
+
+	backend := &LVMBackend{Mounter: m.Mounter}
+	if err := backend.Mount(context.TODO(), nil, mount); err != nil {
+		t.Fatalf("unexpected mount error: %v", err)
+	}
+	m.expectCommands(t, "vgchange --activate y vg", "mount -t xfs /dev/vg/lv "+mount.MountPath)
+
+	if info, err := os.Stat(mount.MountPath); err != nil || !info.Mode().IsRegular() {
+		t.Errorf("expected the mount target to be a file: %v", err)
+	}
+
+	// Once the mount path is listed as mounted, it is unmounted
+	m.setMounts(t, "/dev/mapper/vg-lv "+mount.MountPath+" xfs rw 0 0\n")
+	if err := backend.Unmount(context.TODO(), nil, mount); err != nil {
+		t.Fatalf("unexpected unmount error: %v", err)
+	}
+	m.expectCommands(t, "umount "+mount.MountPath, "vgchange --activate n vg")
+}
+
+func TestNFSBackend(t *testing.T) {
+	m := newTestMounter(t)
+	mount := &dwsv1alpha7.ClientMountInfo{
+		MountPath: filepath.Join(m.dir, "nfs"),
+		Options:   "vers=4.2",
+		Device: dwsv1alpha7.ClientMountDevice{
+			Type: dwsv1alpha7.ClientMountDeviceTypeNFS,
+			NFS:  &dwsv1alpha7.ClientMountDeviceNFS{Server: "nfs-server", Export: "/export/scratch"},
+		},
+		TargetType: "directory",
+	}
+
+	backend := &NFSBackend{Mounter: m.Mounter}
+	if err := backend.Mount(context.TODO(), nil, mount); err != nil {
+		t.Fatalf("unexpected mount error: %v", err)
+	}
+	m.expectCommands(t, "mount -t nfs -o vers=4.2 nfs-server:/export/scratch "+mount.MountPath)
+}
+
+func TestGenericBackend(t *testing.T) {
+	m := newTestMounter(t)
+	mount := &dwsv1alpha7.ClientMountInfo{
+		MountPath: filepath.Join(m.dir, "gpfs"),
+		Device: dwsv1alpha7.ClientMountDevice{
+			Type:    dwsv1alpha7.ClientMountDeviceTypeGeneric,
+			Generic: &dwsv1alpha7.ClientMountDeviceGeneric{Source: "gpfs0", FileSystemType: "gpfs"},
+		},
+		TargetType: "directory",
+	}
+
+	backend := &GenericBackend{Mounter: m.Mounter}
+	if err := backend.Mount(context.TODO(), nil, mount); err != nil {
+		t.Fatalf("unexpected mount error: %v", err)
+	}
+	m.expectCommands(t, "mount -t gpfs gpfs0 "+mount.MountPath)
+}
+
+func TestBindBackend(t *testing.T) {
+	m := newTestMounter(t)
+	mount := &dwsv1alpha7.ClientMountInfo{
+		MountPath: filepath.Join(m.dir, "bind"),
+		Options:   "ro",
+		Device: dwsv1alpha7.ClientMountDevice{
+			Type: dwsv1alpha7.ClientMountDeviceTypeBind,
+			Bind: &dwsv1alpha7.ClientMountDeviceBind{SourcePath: "/local/scratch"},
+		},
+		TargetType: "directory",
+	}
+
+	backend := &BindBackend{Mounter: m.Mounter}
+	if err := backend.Mount(context.TODO(), nil, mount); err != nil {
+		t.Fatalf("unexpected mount error: %v", err)
+	}
+	m.expectCommands(t, "mount -o bind,ro /local/scratch "+mount.MountPath)
+}
+
+func TestReferenceBackend(t *testing.T) {
+	fake := &FakeClientMountBackend{}
+	backend := &ReferenceBackend{Kinds: map[string]ClientMountBackend{"Widget": fake}}
+	clientMount := &dwsv1alpha7.ClientMount{Spec: dwsv1alpha7.ClientMountSpec{Node: "node"}}
+	mount := &dwsv1alpha7.ClientMountInfo{
+		MountPath: "/mnt/widget",
+		Device: dwsv1alpha7.ClientMountDevice{
+			Type:            dwsv1alpha7.ClientMountDeviceTypeReference,
+			DeviceReference: &dwsv1alpha7.ClientMountDeviceReference{ObjectReference: corev1.ObjectReference{Kind: "Widget", Name: "w"}},
+		},
+	}
+
+	if err := backend.Mount(context.TODO(), clientMount, mount); err != nil {
+		t.Fatalf("unexpected mount error: %v", err)
+	}
+	if !fake.IsMounted("node", "/mnt/widget") {
+		t.Errorf("expected the Widget backend to mount the device")
+	}
+
+	mount.Device.DeviceReference.ObjectReference.Kind = "Gadget"
+	if err := backend.Mount(context.TODO(), clientMount, mount); err == nil {
+		t.Errorf("expected an error for a kind without a backend")
+	}
+}
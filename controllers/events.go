@@ -0,0 +1,68 @@
+/*
+ * Copyright 2025 Hewlett Packard Enterprise Development LP
+ * Other additional copyright holders may be indicated within.
+ *
+ * The entirety of this work is licensed under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ *
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+// Reasons for the Events recorded by the DWS controllers. Tooling that watches
+// Events can match on these values.
+const (
+	// EventReasonStateChange is recorded when a resource starts moving to a new desired state
+	EventReasonStateChange = "StateChange"
+
+	// EventReasonReady is recorded when a resource reaches its desired state
+	EventReasonReady = "Ready"
+
+	// EventReasonForceReady is recorded when a Workflow is moved to ready with ForceReady
+	EventReasonForceReady = "ForceReady"
+
+	// EventReasonDriverError is recorded for each driver reporting an error when a Workflow
+	// moves to the Error status
+	EventReasonDriverError = "DriverError"
+
+	// EventReasonCancelled is recorded when the controller accepts a Workflow's cancellation request
+	EventReasonCancelled = "Cancelled"
+
+	// EventReasonSuspended is recorded when a Workflow is suspended
+	EventReasonSuspended = "Suspended"
+
+	// EventReasonResumed is recorded when a suspended Workflow is resumed
+	EventReasonResumed = "Resumed"
+
+	// EventReasonRetry is recorded when the failed drivers of a Workflow are reset for a retry
+	EventReasonRetry = "Retry"
+
+	// EventReasonStateTimeout is recorded when a Workflow doesn't reach its desired state
+	// within the state's timeout
+	EventReasonStateTimeout = "StateTimeout"
+
+	// EventReasonMountFailed is recorded when a ClientMount backend fails to mount or
+	// unmount a device
+	EventReasonMountFailed = "MountFailed"
+
+	// EventReasonChildCreated is recorded when a controller creates a child resource
+	EventReasonChildCreated = "ChildCreated"
+
+	// EventReasonDeletingChildren is recorded while a resource that is being deleted waits
+	// for its child resources to be deleted
+	EventReasonDeletingChildren = "DeletingChildren"
+
+	// EventReasonChildrenDeleted is recorded once all the child resources of a resource
+	// that is being deleted are gone
+	EventReasonChildrenDeleted = "ChildrenDeleted"
+)
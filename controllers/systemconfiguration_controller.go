@@ -21,6 +21,7 @@ package controllers
 
 import (
 	"context"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -31,6 +32,7 @@ import (
 	"github.com/DataWorkflowServices/dws/utils/updater"
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -48,6 +50,7 @@ type SystemConfigurationReconciler struct {
 	client.Client
 	Log          logr.Logger
 	Scheme       *runtime.Scheme
+	Recorder     record.EventRecorder
 	ChildObjects []dwsv1alpha7.ObjectList
 }
 
@@ -55,11 +58,13 @@ type SystemConfigurationReconciler struct {
 // +kubebuilder:rbac:groups=dataworkflowservices.github.io,resources=systemconfigurations/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=dataworkflowservices.github.io,resources=systemconfigurations/finalizers,verbs=update
 // +kubebuilder:rbac:groups=dataworkflowservices.github.io,resources=storages,verbs=get;list;watch;create;update;patch;delete;deletecollection
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 func (r *SystemConfigurationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, err error) {
 	log := r.Log.WithValues("SystemConfiguration", req.NamespacedName)
 
 	metrics.DwsReconcilesTotal.Inc()
+	defer func(start time.Time) { metrics.ObserveReconcile("systemconfiguration", start, err) }(time.Now())
 
 	systemConfiguration := &dwsv1alpha7.SystemConfiguration{}
 	if err := r.Get(ctx, req.NamespacedName, systemConfiguration); err != nil {
@@ -73,6 +78,10 @@ func (r *SystemConfigurationReconciler) Reconcile(ctx context.Context, req ctrl.
 	// in systemConfiguration.Status{} change
 	statusUpdater := updater.NewStatusUpdater[*dwsv1alpha7.SystemConfigurationStatus](systemConfiguration)
 	defer func() { err = statusUpdater.CloseWithStatusUpdate(ctx, r.Client.Status(), err) }()
+	// storagesReady is set once a Storage resource exists for every storage node. The
+	// Status.Ready field is left to whoever owns it; only the conditions are derived here.
+	storagesReady := false
+	defer func() { setSystemConfigurationConditions(systemConfiguration, storagesReady) }()
 	defer func() { systemConfiguration.Status.SetResourceErrorAndLog(err, log) }()
 
 	// Handle cleanup if the resource is being deleted
@@ -87,9 +96,12 @@ func (r *SystemConfigurationReconciler) Reconcile(ctx context.Context, req ctrl.
 		}
 
 		if !deleteStatus.Complete() {
+			r.Recorder.Event(systemConfiguration, corev1.EventTypeNormal, EventReasonDeletingChildren, "Waiting for child resources to be deleted")
 			return ctrl.Result{}, nil
 		}
 
+		r.Recorder.Event(systemConfiguration, corev1.EventTypeNormal, EventReasonChildrenDeleted, "All child resources have been deleted")
+
 		controllerutil.RemoveFinalizer(systemConfiguration, finalizerDWSSystemConfiguration)
 		if err := r.Update(ctx, systemConfiguration); err != nil {
 			if !apierrors.IsConflict(err) {
@@ -141,6 +153,7 @@ func (r *SystemConfigurationReconciler) Reconcile(ctx context.Context, req ctrl.
 
 		if result == controllerutil.OperationResultCreated {
 			log.Info("Created storage", "name", storage.Name)
+			r.Recorder.Eventf(systemConfiguration, corev1.EventTypeNormal, EventReasonChildCreated, "Created Storage %s", storage.Name)
 		} else if result == controllerutil.OperationResultNone {
 			// no change
 		} else {
@@ -148,9 +161,26 @@ func (r *SystemConfigurationReconciler) Reconcile(ctx context.Context, req ctrl.
 		}
 	}
 
+	storagesReady = true
+
 	return ctrl.Result{}, nil
 }
 
+// setSystemConfigurationConditions derives the SystemConfiguration's conditions from the
+// observed state of its Storage resources and its error status
+func setSystemConfigurationConditions(systemConfiguration *dwsv1alpha7.SystemConfiguration, storagesReady bool) {
+	conditions := &systemConfiguration.Status.Conditions
+	generation := systemConfiguration.GetGeneration()
+
+	if storagesReady {
+		dwsv1alpha7.SetCondition(conditions, dwsv1alpha7.ConditionTypeReady, true, dwsv1alpha7.ConditionReasonReady, "", generation)
+	} else {
+		dwsv1alpha7.SetCondition(conditions, dwsv1alpha7.ConditionTypeReady, false, dwsv1alpha7.ConditionReasonNotReady, "", generation)
+	}
+
+	systemConfiguration.Status.SetErrorConditions(conditions, generation)
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *SystemConfigurationReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.ChildObjects = []dwsv1alpha7.ObjectList{
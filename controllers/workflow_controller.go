@@ -25,6 +25,7 @@ import (
 	"reflect"
 	"runtime"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -32,6 +33,8 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -45,6 +48,10 @@ import (
 const (
 	// finalizerDwsWorkflow is the finalizer string used by this controller
 	finalizerDwsWorkflow = "dataworkflowservices.github.io/workflow"
+
+	// staleHeartbeatMessage is part of the message the controller puts in a driver entry
+	// whose heartbeat has gone stale
+	staleHeartbeatMessage = "has not updated its heartbeat"
 )
 
 // Define condition values
@@ -59,11 +66,28 @@ type WorkflowReconciler struct {
 	Scheme       *kruntime.Scheme
 	Log          logr.Logger
 	ChildObjects []dwsv1alpha7.ObjectList
+	Recorder     record.EventRecorder
+
+	// DriverHeartbeatTimeout is the amount of time a driver may go without updating
+	// its heartbeat before it is reported as a TransientCondition. Zero disables the
+	// heartbeat check.
+	DriverHeartbeatTimeout time.Duration
+
+	// DriverHeartbeatErrorTimeout is the amount of time a driver may go without
+	// updating its heartbeat before it is reported as an Error. Zero disables the
+	// escalation to Error.
+	DriverHeartbeatErrorTimeout time.Duration
+
+	// CancelGracePeriod is the amount of time the drivers are given to finish with
+	// the current state of a cancelled workflow before it is moved to Teardown
+	CancelGracePeriod time.Duration
 }
 
 //+kubebuilder:rbac:groups=dataworkflowservices.github.io,resources=workflows,verbs=get;list;watch;update;patch
 //+kubebuilder:rbac:groups=dataworkflowservices.github.io,resources=workflows/finalizers,verbs=update
 //+kubebuilder:rbac:groups=dataworkflowservices.github.io,resources=computes,verbs=get;create;list;watch;update;patch;delete;deletecollection
+//+kubebuilder:rbac:groups=dataworkflowservices.github.io,resources=systemconfigurations,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -79,13 +103,36 @@ func (r *WorkflowReconciler) Reconcile(ctx context.Context, req ctrl.Request) (r
 	log.Info("Reconciling Workflow")
 
 	metrics.DwsReconcilesTotal.Inc()
+	defer func(start time.Time) { metrics.ObserveReconcile("workflow", start, err) }(time.Now())
 
 	// Fetch the Workflow workflow
 	workflow := &dwsv1alpha7.Workflow{}
 	if err := r.Get(ctx, req.NamespacedName, workflow); err != nil {
+		if apierrors.IsNotFound(err) {
+			metrics.ForgetWorkflow(req.NamespacedName.String())
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	// Metrics for the work done by this reconcile are only recorded once the status update
+	// below has succeeded. A conflict leaves the resource version alone, and the work is
+	// done again by the next reconcile.
+	resourceVersion := workflow.GetResourceVersion()
+	observations := []func(){}
+	defer func() {
+		if err != nil || !workflow.GetDeletionTimestamp().IsZero() {
+			return
+		}
+
+		if workflow.GetResourceVersion() != resourceVersion {
+			for _, observe := range observations {
+				observe()
+			}
+		}
+
+		metrics.SetWorkflowStatus(req.NamespacedName.String(), string(workflow.Status.State), workflow.Status.Status)
+	}()
+
 	// Create a status updater that handles the call to r.Update() if any of the fields
 	// in workflow.Status{} change. This is necessary since Status is not a subresource
 	// of the workflow.
@@ -110,14 +157,19 @@ func (r *WorkflowReconciler) Reconcile(ctx context.Context, req ctrl.Request) (r
 		}
 
 		if !DeleteStatus.Complete() {
+			r.Recorder.Event(workflow, v1.EventTypeNormal, EventReasonDeletingChildren, "Waiting for child resources to be deleted")
 			return ctrl.Result{}, nil
 		}
 
+		r.Recorder.Event(workflow, v1.EventTypeNormal, EventReasonChildrenDeleted, "All child resources have been deleted")
+
 		controllerutil.RemoveFinalizer(workflow, finalizerDwsWorkflow)
 		if err := r.Update(ctx, workflow); err != nil {
 			return ctrl.Result{}, err
 		}
 
+		metrics.ForgetWorkflow(req.NamespacedName.String())
+
 		return ctrl.Result{}, nil
 	}
 
@@ -131,16 +183,127 @@ func (r *WorkflowReconciler) Reconcile(ctx context.Context, req ctrl.Request) (r
 		return ctrl.Result{}, nil
 	}
 
+	// Keep the conditions in step with the rest of the status
+	defer func() { setWorkflowConditions(workflow) }()
+
+	// A suspended workflow is left as it is until it's resumed. This includes skipping the
+	// heartbeat, timeout, and cancellation handling.
+	if workflow.Spec.Suspend {
+		if workflow.Status.Suspended == nil {
+			workflow.Status.Suspended = &dwsv1alpha7.WorkflowSuspendStatus{
+				SuspendTime: metav1.NowMicro(),
+				Status:      workflow.Status.Status,
+				Message:     workflow.Status.Message,
+			}
+			log.Info("Workflow suspended", "state", workflow.Status.State)
+			r.Recorder.Eventf(workflow, v1.EventTypeNormal, EventReasonSuspended, "Workflow suspended in state %s", workflow.Status.State)
+		}
+
+		workflow.Status.Status = dwsv1alpha7.StatusSuspended
+		workflow.Status.Message = "Workflow is suspended"
+
+		return ctrl.Result{}, nil
+	}
+
+	// A resumed workflow continues where it left off. The status from before the suspend is
+	// put back so errors aren't reported again, and the time spent suspended doesn't count
+	// against the state timeout.
+	if workflow.Status.Suspended != nil {
+		log.Info("Workflow resumed", "state", workflow.Status.State)
+		r.Recorder.Eventf(workflow, v1.EventTypeNormal, EventReasonResumed, "Workflow resumed in state %s", workflow.Status.State)
+
+		if workflow.Status.StateTimeoutStart != nil {
+			start := metav1.NewMicroTime(workflow.Status.StateTimeoutStart.Add(time.Since(workflow.Status.Suspended.SuspendTime.Time)))
+			workflow.Status.StateTimeoutStart = &start
+		}
+
+		workflow.Status.Status = workflow.Status.Suspended.Status
+		workflow.Status.Message = workflow.Status.Suspended.Message
+		workflow.Status.Suspended = nil
+	}
+
+	// Accept a cancellation request and move the workflow to teardown once the drivers
+	// have had a chance to stop their work
+	cancelWait := time.Duration(0)
+	if workflow.Spec.Cancel != nil {
+		if workflow.Status.Cancelled == nil {
+			workflow.Status.Cancelled = &dwsv1alpha7.WorkflowCancelStatus{
+				Reason:     workflow.Spec.Cancel.Reason,
+				State:      workflow.Status.State,
+				CancelTime: metav1.NowMicro(),
+			}
+			log.Info("Workflow cancelled", "state", workflow.Status.State, "reason", workflow.Spec.Cancel.Reason)
+			r.Recorder.Eventf(workflow, v1.EventTypeNormal, EventReasonCancelled, "Workflow cancelled in state %s: %s", workflow.Status.State, workflow.Spec.Cancel.Reason)
+
+			return ctrl.Result{}, nil
+		}
+
+		if workflow.Spec.DesiredState != dwsv1alpha7.StateTeardown {
+			cancelWait = r.CancelGracePeriod - time.Since(workflow.Status.Cancelled.CancelTime.Time)
+			if workflow.Status.Ready || cancelWait <= 0 {
+				log.Info("Moving cancelled workflow to teardown")
+				workflow.Spec.DesiredState = dwsv1alpha7.StateTeardown
+				workflow.Spec.Hurry = workflow.Spec.Cancel.Hurry
+				if err := r.Update(ctx, workflow); err != nil {
+					if apierrors.IsConflict(err) {
+						return ctrl.Result{Requeue: true}, nil
+					}
+					return ctrl.Result{}, err
+				}
+
+				return ctrl.Result{}, nil
+			}
+		}
+	}
+
 	// Need to set Status.State first because the webhook validates this.
 	if workflow.Status.State != workflow.Spec.DesiredState {
 		log.Info("Workflow state transitioning", "state", workflow.Spec.DesiredState)
+		ts := metav1.NowMicro()
+		endStateHistory(workflow, ts, false)
+		workflow.Status.History = append(workflow.Status.History, dwsv1alpha7.WorkflowStateHistory{
+			State:     workflow.Spec.DesiredState,
+			EnterTime: ts,
+		})
+
 		workflow.Spec.ForceReady = ConditionFalse
 		workflow.Status.State = workflow.Spec.DesiredState
 		workflow.Status.Ready = ConditionFalse
 		workflow.Status.Status = dwsv1alpha7.StatusDriverWait
 		workflow.Status.Message = ""
-		ts := metav1.NowMicro()
 		workflow.Status.DesiredStateChange = &ts
+		workflow.Status.StateTimeoutStart = &ts
+		workflow.Status.StateRetryCount = 0
+		r.Recorder.Eventf(workflow, v1.EventTypeNormal, EventReasonStateChange, "Workflow transitioning to state %s", workflow.Status.State)
+
+		return ctrl.Result{}, nil
+	}
+
+	// Start a retry requested by the WLM. The drivers that failed in the current state are
+	// reset so they pick up the work again. The state timeout starts over, otherwise a retry
+	// after a timeout would fail again straight away. The webhook only allows a retry while
+	// the workflow is in Error.
+	if workflow.Spec.RetryCount != workflow.Status.RetryCount {
+		ts := metav1.NowMicro()
+		workflow.Status.RetryCount = workflow.Spec.RetryCount
+		workflow.Status.StateRetryCount++
+		workflow.Status.StateTimeoutStart = &ts
+
+		for i := range workflow.Status.Drivers {
+			driver := &workflow.Status.Drivers[i]
+			if driver.WatchState != workflow.Status.State || driver.Status != dwsv1alpha7.StatusError {
+				continue
+			}
+
+			driver.Status = dwsv1alpha7.StatusPending
+			driver.Message = ""
+			driver.Error = ""
+		}
+
+		workflow.Status.Status = dwsv1alpha7.StatusDriverWait
+		workflow.Status.Message = ""
+		log.Info("Workflow retry", "state", workflow.Status.State, "retry", workflow.Status.StateRetryCount)
+		r.Recorder.Eventf(workflow, v1.EventTypeNormal, EventReasonRetry, "Retrying the failed drivers in state %s (retry %d)", workflow.Status.State, workflow.Status.StateRetryCount)
 
 		return ctrl.Result{}, nil
 	}
@@ -180,19 +343,38 @@ func (r *WorkflowReconciler) Reconcile(ctx context.Context, req ctrl.Request) (r
 		return ctrl.Result{}, nil
 	}
 
+	// Let the drivers know when the drivers they depend on have finished with this state
+	workflow.Status.UpdateBlockedDrivers(workflow.Status.State)
+
+	// Remember the status from the previous reconcile so driver errors and state
+	// timeouts are only reported once
+	previousStatus := workflow.Status.Status
+	previousMessage := workflow.Status.Message
+
 	workflow.Status.Ready = true
 	workflow.Status.Status = dwsv1alpha7.StatusCompleted
 	workflow.Status.Message = ""
 
 	// Loop through the driver status array find the entries that are for the current state
 	drivers := []dwsv1alpha7.WorkflowDriverStatus{}
+	now := time.Now()
+	requeueAfter := time.Duration(0)
 
-	for _, driver := range workflow.Status.Drivers {
+	for i := range workflow.Status.Drivers {
+		driver := &workflow.Status.Drivers[i]
 		if driver.WatchState != workflow.Status.State {
 			continue
 		}
 
-		drivers = append(drivers, driver)
+		// Check for a stale heartbeat. Any change is made to the driver's entry so that
+		// the conditions and events see the same state as the roll-up below.
+		if wait := r.checkDriverHeartbeat(driver, now); wait > 0 {
+			if requeueAfter == 0 || wait < requeueAfter {
+				requeueAfter = wait
+			}
+		}
+
+		drivers = append(drivers, *driver)
 	}
 
 	if len(drivers) > 0 {
@@ -228,21 +410,131 @@ func (r *WorkflowReconciler) Reconcile(ctx context.Context, req ctrl.Request) (r
 		}
 	}
 
+	// Report the drivers with errors when the workflow first moves to the Error status
+	if workflow.Status.Status == dwsv1alpha7.StatusError && previousStatus != dwsv1alpha7.StatusError {
+		for _, driver := range drivers {
+			if driver.Status != dwsv1alpha7.StatusError {
+				continue
+			}
+
+			reason := driver.Error
+			if reason == "" {
+				reason = driver.Message
+			}
+			driverID := driver.DriverID
+			observations = append(observations, func() { metrics.DwsDriverErrorsTotal.WithLabelValues(driverID).Inc() })
+			r.Recorder.Eventf(workflow, v1.EventTypeWarning, EventReasonDriverError, "DW Directive %d: driver '%s' reported an error: %s", driver.DWDIndex, driver.DriverID, reason)
+		}
+	}
+
 	if workflow.Spec.ForceReady {
 		workflow.Status.Ready = true
 		workflow.Status.Status = dwsv1alpha7.StatusCompleted
 		workflow.Status.Message = "Forced Ready"
 		log.Info("Workflow transitioning to ready with ForceReady")
+		r.Recorder.Eventf(workflow, v1.EventTypeNormal, EventReasonForceReady, "Workflow forced ready in state %s", workflow.Status.State)
 	}
 
 	if workflow.Status.Ready == true {
 		ts := metav1.NowMicro()
 		workflow.Status.ReadyChange = &ts
-		workflow.Status.ElapsedTimeLastState = ts.Time.Sub(workflow.Status.DesiredStateChange.Time).Round(time.Microsecond).String()
+		elapsed := ts.Time.Sub(workflow.Status.DesiredStateChange.Time)
+		workflow.Status.ElapsedTimeLastState = elapsed.Round(time.Microsecond).String()
+		state := string(workflow.Status.State)
+		observations = append(observations, func() { metrics.DwsWorkflowStateDurationSeconds.WithLabelValues(state).Observe(elapsed.Seconds()) })
+		endStateHistory(workflow, ts, true)
 		log.Info("Workflow transitioning to ready", "state", workflow.Status.State)
+		r.Recorder.Eventf(workflow, v1.EventTypeNormal, EventReasonReady, "Workflow reached state %s in %s", workflow.Status.State, workflow.Status.ElapsedTimeLastState)
+
+		return ctrl.Result{}, nil
+	}
+
+	// Check whether the workflow has spent too long trying to reach the current state
+	timeout, err := r.getStateTimeout(ctx, workflow)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if timeout > 0 && workflow.Status.Status != dwsv1alpha7.StatusError {
+		start := workflow.Status.DesiredStateChange
+		if workflow.Status.StateTimeoutStart != nil {
+			start = workflow.Status.StateTimeoutStart
+		}
+
+		elapsed := now.Sub(start.Time)
+		if elapsed >= timeout {
+			workflow.Status.Status = dwsv1alpha7.StatusError
+			workflow.Status.Message = fmt.Sprintf("workflow did not reach state '%s' within the timeout of %s", workflow.Status.State, timeout)
+
+			if previousStatus != workflow.Status.Status || previousMessage != workflow.Status.Message {
+				log.Info("Workflow state timed out", "state", workflow.Status.State, "timeout", timeout)
+				r.Recorder.Event(workflow, v1.EventTypeWarning, EventReasonStateTimeout, workflow.Status.Message)
+			}
+		} else if requeueAfter == 0 || timeout-elapsed < requeueAfter {
+			requeueAfter = timeout - elapsed
+		}
+	}
+
+	if cancelWait > 0 && (requeueAfter == 0 || cancelWait < requeueAfter) {
+		requeueAfter = cancelWait
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// getStateTimeout returns the timeout for the workflow's current state. A timeout in the
+// workflow's spec takes precedence over the site default from the SystemConfiguration. A
+// return value of zero means the state has no timeout.
+func (r *WorkflowReconciler) getStateTimeout(ctx context.Context, workflow *dwsv1alpha7.Workflow) (time.Duration, error) {
+	if timeout, found := dwsv1alpha7.StateTimeout(workflow.Spec.StateTimeouts, workflow.Status.State); found {
+		return timeout, nil
+	}
+
+	systemConfiguration := &dwsv1alpha7.SystemConfiguration{}
+	if err := r.Get(ctx, types.NamespacedName{Name: "default", Namespace: v1.NamespaceDefault}, systemConfiguration); err != nil {
+		// Without a SystemConfiguration there are no site defaults
+		return 0, client.IgnoreNotFound(err)
 	}
 
-	return ctrl.Result{}, nil
+	timeout, _ := dwsv1alpha7.StateTimeout(systemConfiguration.Spec.WorkflowStateTimeouts, workflow.Status.State)
+
+	return timeout, nil
+}
+
+// checkDriverHeartbeat looks for a driver that has stopped updating its heartbeat while
+// it still has work to do in the current state. A stale driver has its status changed to
+// TransientCondition, and then to Error once the error timeout has also passed. A driver
+// that resumes its heartbeat before reaching Error is returned to Running. Drivers that
+// have never set a heartbeat are not checked. The return value is the amount of time
+// until the driver's heartbeat status would next change, or zero if no change is pending.
+func (r *WorkflowReconciler) checkDriverHeartbeat(driver *dwsv1alpha7.WorkflowDriverStatus, now time.Time) time.Duration {
+	if r.DriverHeartbeatTimeout == 0 || driver.LastHB == 0 || driver.Completed || driver.Status == dwsv1alpha7.StatusError {
+		return 0
+	}
+
+	age := now.Sub(time.Unix(driver.LastHB, 0))
+	if age < r.DriverHeartbeatTimeout {
+		if driver.Status == dwsv1alpha7.StatusTransientCondition && strings.Contains(driver.Message, staleHeartbeatMessage) {
+			driver.Status = dwsv1alpha7.StatusRunning
+			driver.Message = ""
+		}
+
+		return r.DriverHeartbeatTimeout - age
+	}
+
+	driver.Message = fmt.Sprintf("driver '%s' for DW Directive %d %s in %s", driver.DriverID, driver.DWDIndex, staleHeartbeatMessage, age.Round(time.Second))
+
+	if r.DriverHeartbeatErrorTimeout != 0 && age >= r.DriverHeartbeatErrorTimeout {
+		driver.Status = dwsv1alpha7.StatusError
+		return 0
+	}
+
+	driver.Status = dwsv1alpha7.StatusTransientCondition
+	if r.DriverHeartbeatErrorTimeout > age {
+		return r.DriverHeartbeatErrorTimeout - age
+	}
+
+	return 0
 }
 
 func (r *WorkflowReconciler) createComputes(ctx context.Context, wf *dwsv1alpha7.Workflow, name string, log logr.Logger) (*dwsv1alpha7.Computes, error) {
@@ -278,6 +570,75 @@ func (r *WorkflowReconciler) createComputes(ctx context.Context, wf *dwsv1alpha7
 	return computes, nil
 }
 
+// endStateHistory fills in the history entry for the workflow's current state using the
+// status the workflow had at time ts. The entry is left alone if it was already filled in
+// when the workflow reached Ready.
+func endStateHistory(workflow *dwsv1alpha7.Workflow, ts metav1.MicroTime, ready bool) {
+	if len(workflow.Status.History) == 0 {
+		return
+	}
+
+	entry := &workflow.Status.History[len(workflow.Status.History)-1]
+	if entry.State != workflow.Status.State || entry.ElapsedTime != "" {
+		return
+	}
+
+	if ready {
+		entry.ReadyTime = &ts
+		entry.ForceReady = workflow.Spec.ForceReady
+	}
+
+	entry.ElapsedTime = ts.Time.Sub(entry.EnterTime.Time).Round(time.Microsecond).String()
+	entry.Status = workflow.Status.Status
+	entry.Message = workflow.Status.Message
+}
+
+// setWorkflowConditions derives the workflow's conditions from its status and the
+// status of the drivers working on its current state
+func setWorkflowConditions(workflow *dwsv1alpha7.Workflow) {
+	conditions := &workflow.Status.Conditions
+	generation := workflow.GetGeneration()
+
+	reason := workflow.Status.Status
+	if reason == "" {
+		reason = dwsv1alpha7.ConditionReasonNotReady
+	}
+	dwsv1alpha7.SetCondition(conditions, dwsv1alpha7.ConditionTypeReady, workflow.Status.Ready, reason, workflow.Status.Message, generation)
+
+	healthy := true
+	for _, driver := range workflow.Status.Drivers {
+		if driver.WatchState != workflow.Status.State || driver.Completed {
+			continue
+		}
+
+		if driver.Status == dwsv1alpha7.StatusTransientCondition || driver.Status == dwsv1alpha7.StatusError {
+			message := driver.Error
+			if message == "" {
+				message = driver.Message
+			}
+			dwsv1alpha7.SetCondition(conditions, dwsv1alpha7.ConditionTypeDriversHealthy, false, driver.Status, fmt.Sprintf("DW Directive %d: driver '%s': %s", driver.DWDIndex, driver.DriverID, message), generation)
+			healthy = false
+			break
+		}
+	}
+
+	if healthy {
+		dwsv1alpha7.SetCondition(conditions, dwsv1alpha7.ConditionTypeDriversHealthy, true, dwsv1alpha7.ConditionReasonDriversHealthy, "", generation)
+	}
+
+	if workflow.Status.Status == dwsv1alpha7.StatusTransientCondition {
+		dwsv1alpha7.SetCondition(conditions, dwsv1alpha7.ConditionTypeDegraded, true, dwsv1alpha7.StatusTransientCondition, workflow.Status.Message, generation)
+	} else {
+		dwsv1alpha7.SetCondition(conditions, dwsv1alpha7.ConditionTypeDegraded, false, dwsv1alpha7.ConditionReasonNoError, "", generation)
+	}
+
+	if workflow.Status.Status == dwsv1alpha7.StatusError {
+		dwsv1alpha7.SetCondition(conditions, dwsv1alpha7.ConditionTypeError, true, dwsv1alpha7.StatusError, workflow.Status.Message, generation)
+	} else {
+		dwsv1alpha7.SetCondition(conditions, dwsv1alpha7.ConditionTypeError, false, dwsv1alpha7.ConditionReasonNoError, "", generation)
+	}
+}
+
 // statusPriority returns the priority of a driver's status. Errors have
 // the lowest priority and completed entries have the lowest priority.
 func statusPriority(status string) int {
@@ -0,0 +1,202 @@
+/*
+ * Copyright 2025 Hewlett Packard Enterprise Development LP
+ * Other additional copyright holders may be indicated within.
+ *
+ * The entirety of this work is licensed under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ *
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dwsv1alpha7 "github.com/DataWorkflowServices/dws/api/v1alpha7"
+)
+
+var _ = Describe("ClientMount Controller Test", func() {
+
+	var (
+		ns          *corev1.Namespace
+		clientMount *dwsv1alpha7.ClientMount
+	)
+
+	BeforeEach(func() {
+		id := uuid.NewString()[0:8]
+
+		// The reconciler only watches the compute namespaces
+		ns = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("compute-%s", id)}}
+		Expect(k8sClient.Create(context.TODO(), ns)).To(Succeed())
+
+		clientMount = &dwsv1alpha7.ClientMount{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      id,
+				Namespace: ns.Name,
+			},
+			Spec: dwsv1alpha7.ClientMountSpec{
+				Node:         ns.Name,
+				DesiredState: dwsv1alpha7.ClientMountStateMounted,
+				Mounts: []dwsv1alpha7.ClientMountInfo{{
+					MountPath: "/mnt/lustre",
+					Device: dwsv1alpha7.ClientMountDevice{
+						Type:   dwsv1alpha7.ClientMountDeviceTypeLustre,
+						Lustre: &dwsv1alpha7.ClientMountDeviceLustre{FileSystemName: "lus", MgsAddresses: "10.0.0.1@tcp"},
+					},
+					Type:       "lustre",
+					TargetType: "directory",
+				}},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		Expect(k8sClient.Delete(context.TODO(), clientMount)).To(Succeed())
+		Eventually(func() error {
+			return k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(clientMount), clientMount)
+		}).ShouldNot(Succeed())
+
+		// Deleting the ClientMount unmounts it
+		Expect(fakeMounts.IsMounted(ns.Name, "/mnt/lustre")).To(BeFalse())
+
+		Expect(k8sClient.Delete(context.TODO(), ns)).To(Succeed())
+	})
+
+	It("Mounts and unmounts with the backend", func() {
+		Expect(k8sClient.Create(context.TODO(), clientMount)).To(Succeed())
+
+		Eventually(func(g Gomega) bool {
+			g.Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(clientMount), clientMount)).To(Succeed())
+			return clientMount.Status.AllReady
+		}).Should(BeTrue())
+		Expect(fakeMounts.IsMounted(ns.Name, "/mnt/lustre")).To(BeTrue())
+
+		Eventually(func() error {
+			latest := &dwsv1alpha7.ClientMount{}
+			if err := k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(clientMount), latest); err != nil {
+				return err
+			}
+			latest.Spec.DesiredState = dwsv1alpha7.ClientMountStateUnmounted
+			return k8sClient.Update(context.TODO(), latest)
+		}).Should(Succeed())
+
+		Eventually(func(g Gomega) bool {
+			g.Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(clientMount), clientMount)).To(Succeed())
+			return clientMount.Status.AllReady && clientMount.Status.Mounts[0].State == dwsv1alpha7.ClientMountStateUnmounted
+		}).Should(BeTrue())
+		Expect(fakeMounts.IsMounted(ns.Name, "/mnt/lustre")).To(BeFalse())
+	})
+
+	It("Reports the mount that failed", func() {
+		fakeMounts.Lock()
+		fakeMounts.Err = errors.New("mount failed")
+		fakeMounts.Unlock()
+
+		Expect(k8sClient.Create(context.TODO(), clientMount)).To(Succeed())
+
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(clientMount), clientMount)).To(Succeed())
+			g.Expect(clientMount.Status.Mounts).To(HaveLen(1))
+			g.Expect(clientMount.Status.Mounts[0].Error).NotTo(BeNil())
+			g.Expect(clientMount.Status.Mounts[0].Message).To(ContainSubstring("/mnt/lustre"))
+			g.Expect(clientMount.Status.Error).NotTo(BeNil())
+			g.Expect(clientMount.Status.Error.DebugMessage).To(ContainSubstring("/mnt/lustre"))
+		}).Should(Succeed())
+		Expect(clientMount.Status.AllReady).To(BeFalse())
+
+		// Let the ClientMount be unmounted and deleted
+		fakeMounts.Lock()
+		fakeMounts.Err = nil
+		fakeMounts.Unlock()
+	})
+
+	It("Mounts nested mounts after the mounts they are in", func() {
+		clientMount.Spec.Mounts = append([]dwsv1alpha7.ClientMountInfo{{
+			MountPath: "/mnt/lustre/job",
+			Device: dwsv1alpha7.ClientMountDevice{
+				Type: dwsv1alpha7.ClientMountDeviceTypeBind,
+				Bind: &dwsv1alpha7.ClientMountDeviceBind{SourcePath: "/local/job"},
+			},
+			Type:       "none",
+			TargetType: "directory",
+		}}, clientMount.Spec.Mounts...)
+
+		Expect(k8sClient.Create(context.TODO(), clientMount)).To(Succeed())
+		Eventually(func(g Gomega) bool {
+			g.Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(clientMount), clientMount)).To(Succeed())
+			return clientMount.Status.AllReady
+		}).Should(BeTrue())
+
+		Eventually(func() error {
+			latest := &dwsv1alpha7.ClientMount{}
+			if err := k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(clientMount), latest); err != nil {
+				return err
+			}
+			latest.Spec.DesiredState = dwsv1alpha7.ClientMountStateUnmounted
+			return k8sClient.Update(context.TODO(), latest)
+		}).Should(Succeed())
+
+		Eventually(func() []string { return fakeMounts.Operations(ns.Name) }).Should(Equal([]string{
+			"mount /mnt/lustre",
+			"mount /mnt/lustre/job",
+			"unmount /mnt/lustre/job",
+			"unmount /mnt/lustre",
+		}))
+	})
+
+	It("Reports a busy mount until it is unmounted lazily", func() {
+		Expect(k8sClient.Create(context.TODO(), clientMount)).To(Succeed())
+		Eventually(func(g Gomega) bool {
+			g.Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(clientMount), clientMount)).To(Succeed())
+			return clientMount.Status.AllReady
+		}).Should(BeTrue())
+
+		fakeMounts.SetBusy(ns.Name, "/mnt/lustre", true)
+		DeferCleanup(func() { fakeMounts.SetBusy(ns.Name, "/mnt/lustre", false) })
+
+		updateSpec := func(modify func(spec *dwsv1alpha7.ClientMountSpec)) {
+			Eventually(func() error {
+				latest := &dwsv1alpha7.ClientMount{}
+				if err := k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(clientMount), latest); err != nil {
+					return err
+				}
+				modify(&latest.Spec)
+				return k8sClient.Update(context.TODO(), latest)
+			}).Should(Succeed())
+		}
+
+		updateSpec(func(spec *dwsv1alpha7.ClientMountSpec) { spec.DesiredState = dwsv1alpha7.ClientMountStateUnmounted })
+		Eventually(func(g Gomega) bool {
+			g.Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(clientMount), clientMount)).To(Succeed())
+			return clientMount.Status.Mounts[0].Busy
+		}).Should(BeTrue())
+		Expect(fakeMounts.IsMounted(ns.Name, "/mnt/lustre")).To(BeTrue())
+
+		updateSpec(func(spec *dwsv1alpha7.ClientMountSpec) { spec.UnmountPolicy = dwsv1alpha7.ClientMountUnmountPolicyLazy })
+		Eventually(func(g Gomega) bool {
+			g.Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(clientMount), clientMount)).To(Succeed())
+			return clientMount.Status.AllReady && !clientMount.Status.Mounts[0].Busy
+		}).Should(BeTrue())
+		Expect(fakeMounts.IsMounted(ns.Name, "/mnt/lustre")).To(BeFalse())
+	})
+})
@@ -62,11 +62,14 @@ var _ = Describe("Conversion Webhook Test", func() {
 					DesiredState: "unmounted",
 					Mounts: []dwsv1alpha7.ClientMountInfo{
 						{
-							MountPath:      "",
+							MountPath:      "/mnt/test",
 							SetPermissions: false,
 							Options:        "",
 							Device: dwsv1alpha7.ClientMountDevice{
 								Type: "reference",
+								DeviceReference: &dwsv1alpha7.ClientMountDeviceReference{
+									ObjectReference: corev1.ObjectReference{Name: "test-device"},
+								},
 							},
 							Type:       "none",
 							TargetType: "directory",
@@ -1,6 +1,9 @@
 package metrics
 
 import (
+	"sync"
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
@@ -12,8 +15,111 @@ var (
 			Help: "Number of total reconciles in DWS controller",
 		},
 	)
+
+	DwsReconcileDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "dws_reconcile_duration_seconds",
+			Help:    "Time spent in a reconcile by each DWS controller",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"controller"},
+	)
+
+	DwsReconcileErrorsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dws_reconcile_errors_total",
+			Help: "Number of reconciles that returned an error in each DWS controller",
+		},
+		[]string{"controller"},
+	)
+
+	DwsWorkflowStateDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "dws_workflow_state_duration_seconds",
+			Help: "Time taken by workflows to reach each state, from the desiredState change to Ready",
+			// 1 second to about 18 hours
+			Buckets: prometheus.ExponentialBuckets(1, 4, 9),
+		},
+		[]string{"state"},
+	)
+
+	DwsDriverErrorsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dws_driver_errors_total",
+			Help: "Number of driver errors that moved a workflow to the Error status",
+		},
+		[]string{"driver_id"},
+	)
+
+	DwsWorkflows = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "dws_workflows",
+			Help: "Number of workflows in each state and status",
+		},
+		[]string{"state", "status"},
+	)
+)
+
+// workflowStateStatus is the state and status last reported for a workflow
+type workflowStateStatus struct {
+	state  string
+	status string
+}
+
+var (
+	workflowsMutex sync.Mutex
+	workflows      = map[string]workflowStateStatus{}
 )
 
 func init() {
 	metrics.Registry.MustRegister(DwsReconcilesTotal)
+	metrics.Registry.MustRegister(DwsReconcileDurationSeconds)
+	metrics.Registry.MustRegister(DwsReconcileErrorsTotal)
+	metrics.Registry.MustRegister(DwsWorkflowStateDurationSeconds)
+	metrics.Registry.MustRegister(DwsDriverErrorsTotal)
+	metrics.Registry.MustRegister(DwsWorkflows)
+}
+
+// ObserveReconcile records the duration of a reconcile that started at start, and
+// counts it as an error if err is not nil
+func ObserveReconcile(controller string, start time.Time, err error) {
+	DwsReconcileDurationSeconds.WithLabelValues(controller).Observe(time.Since(start).Seconds())
+	if err != nil {
+		DwsReconcileErrorsTotal.WithLabelValues(controller).Inc()
+	}
+}
+
+// SetWorkflowStatus moves the workflow identified by name to the state and status in
+// the dws_workflows gauge. The workflow is counted once no matter how many times
+// it is set.
+func SetWorkflowStatus(name string, state string, status string) {
+	workflowsMutex.Lock()
+	defer workflowsMutex.Unlock()
+
+	current := workflowStateStatus{state, status}
+	previous, found := workflows[name]
+	if found && previous == current {
+		return
+	}
+
+	if found {
+		DwsWorkflows.WithLabelValues(previous.state, previous.status).Dec()
+	}
+
+	DwsWorkflows.WithLabelValues(current.state, current.status).Inc()
+	workflows[name] = current
+}
+
+// ForgetWorkflow removes the workflow identified by name from the dws_workflows gauge
+func ForgetWorkflow(name string) {
+	workflowsMutex.Lock()
+	defer workflowsMutex.Unlock()
+
+	previous, found := workflows[name]
+	if !found {
+		return
+	}
+
+	DwsWorkflows.WithLabelValues(previous.state, previous.status).Dec()
+	delete(workflows, name)
 }
@@ -0,0 +1,74 @@
+/*
+ * Copyright 2025 Hewlett Packard Enterprise Development LP
+ * Other additional copyright holders may be indicated within.
+ *
+ * The entirety of this work is licensed under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ *
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestObserveReconcile(t *testing.T) {
+	ObserveReconcile("test", time.Now(), nil)
+	ObserveReconcile("test", time.Now(), errors.New("failed"))
+
+	if count := testutil.CollectAndCount(DwsReconcileDurationSeconds, "dws_reconcile_duration_seconds"); count != 1 {
+		t.Errorf("expected one histogram for the controller, found %d", count)
+	}
+
+	if failures := testutil.ToFloat64(DwsReconcileErrorsTotal.WithLabelValues("test")); failures != 1 {
+		t.Errorf("expected one reconcile error, found %v", failures)
+	}
+}
+
+func TestWorkflowStatus(t *testing.T) {
+	count := func(state string, status string) float64 {
+		return testutil.ToFloat64(DwsWorkflows.WithLabelValues(state, status))
+	}
+
+	expect := func(state string, status string, expected float64) {
+		t.Helper()
+		if actual := count(state, status); actual != expected {
+			t.Errorf("expected %v workflows in %s/%s, found %v", expected, state, status, actual)
+		}
+	}
+
+	// A workflow is only counted once no matter how many times it is set
+	SetWorkflowStatus("default/a", "Proposal", "DriverWait")
+	SetWorkflowStatus("default/a", "Proposal", "DriverWait")
+	SetWorkflowStatus("default/b", "Proposal", "DriverWait")
+	expect("Proposal", "DriverWait", 2)
+
+	// Moving a workflow removes it from its previous state and status
+	SetWorkflowStatus("default/a", "Proposal", "Completed")
+	expect("Proposal", "DriverWait", 1)
+	expect("Proposal", "Completed", 1)
+
+	ForgetWorkflow("default/a")
+	ForgetWorkflow("default/a")
+	ForgetWorkflow("default/unknown")
+	expect("Proposal", "Completed", 0)
+	expect("Proposal", "DriverWait", 1)
+
+	ForgetWorkflow("default/b")
+	expect("Proposal", "DriverWait", 0)
+}
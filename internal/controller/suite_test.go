@@ -20,6 +20,7 @@ import (
 	"context"
 	"path/filepath"
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -52,6 +53,9 @@ var testEnv *envtest.Environment
 var ctx context.Context
 var cancel context.CancelFunc
 
+// fakeMounts records what the ClientMount reconciler mounted
+var fakeMounts *controllers.FakeClientMountBackend
+
 func TestAPIs(t *testing.T) {
 	RegisterFailHandler(Fail)
 
@@ -162,9 +166,24 @@ var _ = BeforeSuite(func() {
 	// start reconcilers
 
 	err = (&controllers.WorkflowReconciler{
-		Client: k8sManager.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("Workflow"),
-		Scheme: testEnv.Scheme,
+		Client:   k8sManager.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("Workflow"),
+		Scheme:   testEnv.Scheme,
+		Recorder: k8sManager.GetEventRecorderFor("dws-workflow"),
+
+		DriverHeartbeatTimeout:      5 * time.Minute,
+		DriverHeartbeatErrorTimeout: time.Hour,
+	}).SetupWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
+	var backends controllers.ClientMountBackends
+	backends, fakeMounts = controllers.NewFakeClientMountBackends()
+	err = (&controllers.ClientMountReconciler{
+		Client:   k8sManager.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("ClientMount"),
+		Scheme:   testEnv.Scheme,
+		Recorder: k8sManager.GetEventRecorderFor("dws-clientmount"),
+		Backends: backends,
 	}).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
@@ -22,6 +22,7 @@ package controller
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	. "github.com/onsi/ginkgo/v2"
@@ -33,6 +34,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	dwsv1alpha7 "github.com/DataWorkflowServices/dws/api/v1alpha7"
+	"github.com/DataWorkflowServices/dws/controllers"
+	"github.com/DataWorkflowServices/dws/utils/dwdparse"
 )
 
 var _ = Describe("Workflow Controller Test", func() {
@@ -80,6 +83,288 @@ var _ = Describe("Workflow Controller Test", func() {
 
 	})
 
+	It("Moves a cancelled workflow to teardown", func() {
+		Expect(k8sClient.Create(context.TODO(), wf)).To(Succeed())
+
+		Eventually(func(g Gomega) string {
+			g.Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(wf), wf)).To(Succeed())
+			return wf.Status.Status
+		}).Should(Equal(dwsv1alpha7.StatusCompleted))
+
+		wf.Spec.Cancel = &dwsv1alpha7.WorkflowCancel{Reason: "job cancelled", Hurry: true}
+		Expect(k8sClient.Update(context.TODO(), wf)).To(Succeed())
+
+		Eventually(func(g Gomega) dwsv1alpha7.WorkflowState {
+			g.Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(wf), wf)).To(Succeed())
+			return wf.Status.State
+		}).Should(Equal(dwsv1alpha7.StateTeardown))
+
+		Expect(wf.Spec.Hurry).To(BeTrue())
+		Expect(wf.Status.Cancelled).ToNot(BeNil())
+		Expect(wf.Status.Cancelled.Reason).To(Equal("job cancelled"))
+		Expect(wf.Status.Cancelled.State).To(Equal(dwsv1alpha7.StateProposal))
+	})
+
+	It("Suspends and resumes a workflow", func() {
+		Expect(k8sClient.Create(context.TODO(), wf)).To(Succeed())
+
+		Eventually(func(g Gomega) string {
+			g.Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(wf), wf)).To(Succeed())
+			return wf.Status.Status
+		}).Should(Equal(dwsv1alpha7.StatusCompleted))
+
+		wf.Spec.Suspend = true
+		Expect(k8sClient.Update(context.TODO(), wf)).To(Succeed())
+
+		Eventually(func(g Gomega) string {
+			g.Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(wf), wf)).To(Succeed())
+			return wf.Status.Status
+		}).Should(Equal(dwsv1alpha7.StatusSuspended))
+
+		wf.Spec.DesiredState = dwsv1alpha7.StateSetup
+		Expect(k8sClient.Update(context.TODO(), wf)).ToNot(Succeed())
+
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(wf), wf)).To(Succeed())
+		wf.Spec.Suspend = false
+		wf.Spec.DesiredState = dwsv1alpha7.StateSetup
+		Expect(k8sClient.Update(context.TODO(), wf)).To(Succeed())
+
+		Eventually(func(g Gomega) bool {
+			g.Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(wf), wf)).To(Succeed())
+			return wf.Status.State == dwsv1alpha7.StateSetup && wf.Status.Status == dwsv1alpha7.StatusCompleted
+		}).Should(BeTrue())
+	})
+
+	It("Doesn't count the time a workflow is suspended against its state timeout", func() {
+		createDriverRule("suspendtimeout")
+
+		wf.Spec.DWDirectives = []string{"#DW suspendtimeout name=slow"}
+		wf.Spec.StateTimeouts = []dwsv1alpha7.WorkflowStateTimeout{
+			{State: dwsv1alpha7.StateProposal, Timeout: metav1.Duration{Duration: 4 * time.Second}},
+		}
+		Eventually(func() error {
+			return k8sClient.Create(context.TODO(), wf)
+		}).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(wf), wf)).To(Succeed())
+			g.Expect(wf.Status.Status).To(Equal(dwsv1alpha7.StatusDriverWait))
+			wf.Spec.Suspend = true
+			g.Expect(k8sClient.Update(context.TODO(), wf)).To(Succeed())
+		}).Should(Succeed())
+
+		// Hold the workflow past its timeout
+		Consistently(func(g Gomega) string {
+			g.Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(wf), wf)).To(Succeed())
+			return wf.Status.Status
+		}, "5s").ShouldNot(Equal(dwsv1alpha7.StatusError))
+
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(wf), wf)).To(Succeed())
+			g.Expect(wf.Status.Status).To(Equal(dwsv1alpha7.StatusSuspended))
+			wf.Spec.Suspend = false
+			g.Expect(k8sClient.Update(context.TODO(), wf)).To(Succeed())
+		}).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(wf), wf)).To(Succeed())
+			g.Expect(wf.Status.Suspended).To(BeNil())
+			g.Expect(wf.Status.Status).To(Equal(dwsv1alpha7.StatusDriverWait))
+			g.Expect(wf.Status.StateTimeoutStart.Sub(wf.Status.DesiredStateChange.Time)).To(BeNumerically(">=", 4*time.Second))
+		}).Should(Succeed())
+
+		// The rest of the timeout still applies
+		Eventually(func(g Gomega) string {
+			g.Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(wf), wf)).To(Succeed())
+			return wf.Status.Status
+		}).Should(Equal(dwsv1alpha7.StatusError))
+	})
+
+	It("Restores the status of a workflow when it is resumed", func() {
+		createDriverRule("suspenderror")
+
+		wf.Spec.DWDirectives = []string{"#DW suspenderror name=broken"}
+		Eventually(func() error {
+			return k8sClient.Create(context.TODO(), wf)
+		}).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(wf), wf)).To(Succeed())
+			g.Expect(wf.Status.State).To(Equal(dwsv1alpha7.StateProposal))
+			g.Expect(wf.Status.Drivers).To(HaveLen(1))
+
+			wf.Status.Drivers[0].Status = dwsv1alpha7.StatusError
+			wf.Status.Drivers[0].Error = "device not found"
+			g.Expect(k8sClient.Update(context.TODO(), wf)).To(Succeed())
+		}).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(wf), wf)).To(Succeed())
+			g.Expect(wf.Status.Status).To(Equal(dwsv1alpha7.StatusError))
+			wf.Spec.Suspend = true
+			g.Expect(k8sClient.Update(context.TODO(), wf)).To(Succeed())
+		}).Should(Succeed())
+		message := wf.Status.Message
+
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(wf), wf)).To(Succeed())
+			g.Expect(wf.Status.Status).To(Equal(dwsv1alpha7.StatusSuspended))
+			wf.Spec.Suspend = false
+			g.Expect(k8sClient.Update(context.TODO(), wf)).To(Succeed())
+		}).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(wf), wf)).To(Succeed())
+			g.Expect(wf.Status.Suspended).To(BeNil())
+			g.Expect(wf.Status.Status).To(Equal(dwsv1alpha7.StatusError))
+			g.Expect(wf.Status.Message).To(Equal(message))
+		}).Should(Succeed())
+
+		// The driver's error is only reported once
+		Consistently(func(g Gomega) int32 {
+			return workflowEventCount(g, wf, controllers.EventReasonDriverError)
+		}, "2s").Should(BeEquivalentTo(1))
+	})
+
+	It("Records the state history", func() {
+		Expect(k8sClient.Create(context.TODO(), wf)).To(Succeed())
+
+		Eventually(func(g Gomega) bool {
+			g.Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(wf), wf)).To(Succeed())
+			return wf.Status.Ready
+		}).Should(BeTrue())
+
+		Expect(wf.Status.History).To(HaveLen(1))
+		Expect(wf.Status.History[0].State).To(Equal(dwsv1alpha7.StateProposal))
+		Expect(wf.Status.History[0].ReadyTime).ToNot(BeNil())
+		Expect(wf.Status.History[0].Status).To(Equal(dwsv1alpha7.StatusCompleted))
+
+		wf.Spec.DesiredState = dwsv1alpha7.StateTeardown
+		Expect(k8sClient.Update(context.TODO(), wf)).To(Succeed())
+
+		Eventually(func(g Gomega) []dwsv1alpha7.WorkflowStateHistory {
+			g.Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(wf), wf)).To(Succeed())
+			return wf.Status.History
+		}).Should(HaveLen(2))
+
+		Expect(wf.Status.History[1].State).To(Equal(dwsv1alpha7.StateTeardown))
+	})
+
+	It("Reports a driver whose heartbeat is stale", func() {
+		createDriverRule("heartbeat")
+
+		wf.Spec.DWDirectives = []string{
+			"#DW heartbeat name=stale",
+			"#DW heartbeat name=fresh",
+		}
+		Eventually(func() error {
+			return k8sClient.Create(context.TODO(), wf)
+		}).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(wf), wf)).To(Succeed())
+			g.Expect(wf.Status.State).To(Equal(dwsv1alpha7.StateProposal))
+			g.Expect(wf.Status.Drivers).To(HaveLen(2))
+
+			// The driver for the first directive stopped its heartbeat ten minutes ago, and
+			// the driver for the second directive is still alive.
+			for i := range wf.Status.Drivers {
+				wf.Status.Drivers[i].Status = dwsv1alpha7.StatusRunning
+				wf.Status.Drivers[i].LastHB = time.Now().Unix()
+				if wf.Status.Drivers[i].DWDIndex == 0 {
+					wf.Status.Drivers[i].LastHB = time.Now().Add(-10 * time.Minute).Unix()
+				}
+			}
+			g.Expect(k8sClient.Update(context.TODO(), wf)).To(Succeed())
+		}).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(wf), wf)).To(Succeed())
+			for _, driver := range wf.Status.Drivers {
+				if driver.DWDIndex == 0 {
+					g.Expect(driver.Status).To(Equal(dwsv1alpha7.StatusTransientCondition))
+					g.Expect(driver.Message).To(ContainSubstring("DW Directive 0"))
+				} else {
+					g.Expect(driver.Status).To(Equal(dwsv1alpha7.StatusRunning))
+					g.Expect(driver.Message).To(BeEmpty())
+				}
+			}
+			g.Expect(wf.Status.Status).To(Equal(dwsv1alpha7.StatusTransientCondition))
+		}).Should(Succeed())
+	})
+
+	It("Records events for a state change and a state timeout", func() {
+		createDriverRule("eventtimeout")
+
+		wf.Spec.DWDirectives = []string{"#DW eventtimeout name=slow"}
+		wf.Spec.StateTimeouts = []dwsv1alpha7.WorkflowStateTimeout{
+			{State: dwsv1alpha7.StateProposal, Timeout: metav1.Duration{Duration: 2 * time.Second}},
+		}
+		Eventually(func() error {
+			return k8sClient.Create(context.TODO(), wf)
+		}).Should(Succeed())
+
+		Eventually(func(g Gomega) []string {
+			return workflowEventReasons(g, wf)
+		}).Should(ContainElements(controllers.EventReasonStateChange, controllers.EventReasonStateTimeout))
+	})
+
+	It("Records an event for a driver error", func() {
+		createDriverRule("eventerror")
+
+		wf.Spec.DWDirectives = []string{"#DW eventerror name=broken"}
+		Eventually(func() error {
+			return k8sClient.Create(context.TODO(), wf)
+		}).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(wf), wf)).To(Succeed())
+			g.Expect(wf.Status.State).To(Equal(dwsv1alpha7.StateProposal))
+			g.Expect(wf.Status.Drivers).To(HaveLen(1))
+
+			wf.Status.Drivers[0].Status = dwsv1alpha7.StatusError
+			wf.Status.Drivers[0].Error = "device not found"
+			g.Expect(k8sClient.Update(context.TODO(), wf)).To(Succeed())
+		}).Should(Succeed())
+
+		Eventually(func(g Gomega) []string {
+			return workflowEventReasons(g, wf)
+		}).Should(ContainElement(controllers.EventReasonDriverError))
+	})
+
+	It("Restarts the state timeout when a workflow is retried", func() {
+		createDriverRule("retrytimeout")
+
+		wf.Spec.DWDirectives = []string{"#DW retrytimeout name=slow"}
+		wf.Spec.StateTimeouts = []dwsv1alpha7.WorkflowStateTimeout{
+			{State: dwsv1alpha7.StateProposal, Timeout: metav1.Duration{Duration: 2 * time.Second}},
+		}
+		Eventually(func() error {
+			return k8sClient.Create(context.TODO(), wf)
+		}).Should(Succeed())
+
+		Eventually(func(g Gomega) string {
+			g.Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(wf), wf)).To(Succeed())
+			return wf.Status.Status
+		}).Should(Equal(dwsv1alpha7.StatusError))
+		desiredStateChange := wf.Status.DesiredStateChange.DeepCopy()
+		timedOut := wf.Status.StateTimeoutStart.DeepCopy()
+
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(wf), wf)).To(Succeed())
+			wf.Spec.RetryCount = 1
+			g.Expect(k8sClient.Update(context.TODO(), wf)).To(Succeed())
+		}).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(wf), wf)).To(Succeed())
+			g.Expect(wf.Status.StateRetryCount).To(Equal(1))
+			g.Expect(wf.Status.Status).To(Equal(dwsv1alpha7.StatusDriverWait))
+			g.Expect(wf.Status.StateTimeoutStart.After(timedOut.Time)).To(BeTrue())
+			g.Expect(wf.Status.DesiredStateChange.Equal(desiredStateChange)).To(BeTrue())
+		}).Should(Succeed())
+	})
+
 	It("Fails to create workflow with hurry flag set", func() {
 		wf.Spec.Hurry = true
 		Expect(k8sClient.Create(context.TODO(), wf)).ToNot(Succeed())
@@ -111,3 +396,55 @@ var _ = Describe("Workflow Controller Test", func() {
 		Expect(k8sClient.Update(context.TODO(), wf)).To(Succeed())
 	})
 })
+
+// createDriverRule creates a DWDirectiveRule for the #DW command that registers a driver
+// for the Proposal state. The rule is deleted when the spec finishes.
+func createDriverRule(command string) {
+	rule := &dwsv1alpha7.DWDirectiveRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      command + "-rules",
+			Namespace: corev1.NamespaceDefault,
+		},
+		Spec: []dwdparse.DWDirectiveRuleSpec{{
+			Command:     command,
+			DriverLabel: command + "-driver",
+			WatchStates: string(dwsv1alpha7.StateProposal),
+			RuleDefs: []dwdparse.DWDirectiveRuleDef{
+				{Key: "name", Type: "string", IsRequired: true, IsValueRequired: true},
+			},
+		}},
+	}
+	Expect(k8sClient.Create(context.TODO(), rule)).To(Succeed())
+	DeferCleanup(func() { Expect(k8sClient.Delete(context.TODO(), rule)).To(Succeed()) })
+}
+
+// workflowEventReasons returns the reasons of the Events recorded for the workflow
+func workflowEventReasons(g Gomega, wf *dwsv1alpha7.Workflow) []string {
+	events := &corev1.EventList{}
+	g.Expect(k8sClient.List(context.TODO(), events, client.InNamespace(wf.Namespace))).To(Succeed())
+
+	reasons := []string{}
+	for _, event := range events.Items {
+		if event.InvolvedObject.Kind == "Workflow" && event.InvolvedObject.Name == wf.Name {
+			reasons = append(reasons, event.Reason)
+		}
+	}
+
+	return reasons
+}
+
+// workflowEventCount returns the number of times an Event with the reason was recorded for
+// the workflow. Repeats of an Event are counted in the Event rather than recorded again.
+func workflowEventCount(g Gomega, wf *dwsv1alpha7.Workflow, reason string) int32 {
+	events := &corev1.EventList{}
+	g.Expect(k8sClient.List(context.TODO(), events, client.InNamespace(wf.Namespace))).To(Succeed())
+
+	count := int32(0)
+	for _, event := range events.Items {
+		if event.InvolvedObject.Kind == "Workflow" && event.InvolvedObject.Name == wf.Name && event.Reason == reason {
+			count += event.Count
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,164 @@
+/*
+ * Copyright 2025 Hewlett Packard Enterprise Development LP
+ * Other additional copyright holders may be indicated within.
+ *
+ * The entirety of this work is licensed under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ *
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dwdparse
+
+import (
+	"time"
+)
+
+// Directive is a #DW directive that has been validated against a rule, with its argument
+// values converted to the types given by the rule's definitions
+type Directive struct {
+	// Command is the #DW command, such as jobdw or copy_in
+	Command string
+
+	// Args holds the value of each argument, keyed by the argument's name. The Go type of
+	// each value depends on the type of its rule definition; see DWDirectiveRuleDef.ParseValue.
+	Args map[string]interface{}
+}
+
+// Parse validates a directive against a rule and returns the directive with its argument
+// values converted. The rule's defaults are applied for the arguments that the directive
+// doesn't have. An argument given without a value is true for a bool, and an empty string
+// for a string or list-of-string. The other types require a value.
+func Parse(spec DWDirectiveRuleSpec, dwd string) (*Directive, error) {
+	argsMap, err := BuildArgsMap(dwd)
+	if err != nil {
+		return nil, ValidationErrors{err.(*ValidationError)}
+	}
+
+	rule := compileRule(spec)
+	return rule.parse(argsMap)
+}
+
+// parse converts the values of arguments that have been split out of a directive
+func (r *compiledRule) parse(args map[string]string) (*Directive, error) {
+	if errs := r.validateArgs(args, map[string]bool{}); len(errs) > 0 {
+		return nil, errs
+	}
+
+	directive := &Directive{
+		Command: args["command"],
+		Args:    map[string]interface{}{},
+	}
+
+	for k, v := range args {
+		if k == "command" {
+			continue
+		}
+
+		compiled, verr := r.findRuleDefinition(k)
+		if verr != nil {
+			return nil, ValidationErrors{verr}
+		}
+
+		if v == emptyValue {
+			v = ""
+		}
+
+		value, err := compiled.def.ParseValue(v)
+		if err != nil {
+			return nil, ValidationErrors{newValidationError(ReasonInvalidValue, k, compiled.def.Key, "argument '%s' %s", k, err.Error())}
+		}
+
+		directive.Args[k] = value
+	}
+
+	for k, v := range r.defaults(args) {
+		compiled, verr := r.findRuleDefinition(k)
+		if verr != nil {
+			return nil, ValidationErrors{verr}
+		}
+
+		value, err := compiled.def.ParseValue(v)
+		if err != nil {
+			return nil, ValidationErrors{newValidationError(ReasonInvalidRule, k, compiled.def.Key, "rule '%s' default value: %s", compiled.def.Key, err.Error())}
+		}
+
+		directive.Args[k] = value
+	}
+
+	return directive, nil
+}
+
+// Defaults returns the default values that the rule provides for the arguments that the
+// directive doesn't have. The directive must be valid for the rule.
+func Defaults(spec DWDirectiveRuleSpec, dwd string) (map[string]string, error) {
+	argsMap, err := BuildArgsMap(dwd)
+	if err != nil {
+		return nil, ValidationErrors{err.(*ValidationError)}
+	}
+
+	rule := compileRule(spec)
+	if errs := rule.validateArgs(argsMap, map[string]bool{}); len(errs) > 0 {
+		return nil, errs
+	}
+
+	return rule.defaults(argsMap), nil
+}
+
+// Has returns true if the directive has the argument. The typed accessors below return
+// false as their second value when the argument is missing or is of a different type.
+func (d *Directive) Has(key string) bool {
+	_, found := d.Args[key]
+	return found
+}
+
+// String returns the value of a string or enum argument
+func (d *Directive) String(key string) (string, bool) {
+	value, ok := d.Args[key].(string)
+	return value, ok
+}
+
+// Int returns the value of an integer argument
+func (d *Directive) Int(key string) (int, bool) {
+	value, ok := d.Args[key].(int)
+	return value, ok
+}
+
+// Bool returns the value of a bool argument
+func (d *Directive) Bool(key string) (bool, bool) {
+	value, ok := d.Args[key].(bool)
+	return value, ok
+}
+
+// Strings returns the words of a list-of-string argument
+func (d *Directive) Strings(key string) ([]string, bool) {
+	value, ok := d.Args[key].([]string)
+	return value, ok
+}
+
+// Size returns the number of bytes of a size argument
+func (d *Directive) Size(key string) (int64, bool) {
+	value, ok := d.Args[key].(int64)
+	return value, ok
+}
+
+// Duration returns the value of a duration argument
+func (d *Directive) Duration(key string) (time.Duration, bool) {
+	value, ok := d.Args[key].(time.Duration)
+	return value, ok
+}
+
+// Float returns the value of a float argument
+func (d *Directive) Float(key string) (float64, bool) {
+	value, ok := d.Args[key].(float64)
+	return value, ok
+}
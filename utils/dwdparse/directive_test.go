@@ -0,0 +1,176 @@
+/*
+ * Copyright 2025 Hewlett Packard Enterprise Development LP
+ * Other additional copyright holders may be indicated within.
+ *
+ * The entirety of this work is licensed under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ *
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dwdparse
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	spec := DWDirectiveRuleSpec{
+		Command: "jobdw",
+		RuleDefs: []DWDirectiveRuleDef{
+			{Key: "type", Type: RuleTypeEnum, Enum: []string{"xfs", "lustre"}, IsRequired: true, IsValueRequired: true},
+			{Key: "capacity", Type: RuleTypeSize, IsRequired: true, IsValueRequired: true},
+			{Key: "name", Type: RuleTypeString, IsRequired: true, IsValueRequired: true},
+			{Key: "count", Type: RuleTypeInteger},
+			{Key: "verbose", Type: RuleTypeBool},
+			{Key: "debug", Type: RuleTypeBool},
+			{Key: "requires", Type: RuleTypeListOfString},
+			{Key: "timeout", Type: RuleTypeDuration},
+			{Key: "ratio", Type: RuleTypeFloat},
+			{Key: "description", Type: RuleTypeString},
+		},
+	}
+
+	directive, err := Parse(spec, `#DW jobdw type=lustre capacity=10GiB name="my fs" count=3 verbose debug=false requires=a,b timeout=5m ratio=0.5 description`)
+	if err != nil {
+		t.Fatalf("TestParse: unexpected error: %v", err)
+	}
+
+	if directive.Command != "jobdw" {
+		t.Errorf("TestParse: expected command jobdw, got %s", directive.Command)
+	}
+
+	expected := map[string]interface{}{
+		"type":        "lustre",
+		"capacity":    int64(10 << 30),
+		"name":        "my fs",
+		"count":       3,
+		"verbose":     true,
+		"debug":       false,
+		"requires":    []string{"a", "b"},
+		"timeout":     5 * time.Minute,
+		"ratio":       0.5,
+		"description": "",
+	}
+	if !reflect.DeepEqual(directive.Args, expected) {
+		t.Errorf("TestParse: expected %v, got %v", expected, directive.Args)
+	}
+
+	if size, ok := directive.Size("capacity"); !ok || size != 10<<30 {
+		t.Errorf("TestParse: expected capacity of %d, got %d", int64(10<<30), size)
+	}
+
+	if verbose, ok := directive.Bool("verbose"); !ok || !verbose {
+		t.Errorf("TestParse: expected verbose to be true")
+	}
+
+	if _, ok := directive.Int("name"); ok {
+		t.Errorf("TestParse: expected name not to be an integer")
+	}
+
+	if directive.Has("missing") {
+		t.Errorf("TestParse: expected no 'missing' argument")
+	}
+
+	if _, err := Parse(spec, "#DW jobdw type=gfs2 capacity=1GiB name=x"); err == nil {
+		t.Errorf("TestParse: expected an error for an invalid enum value")
+	}
+
+	if _, err := Parse(spec, "#DW create_persistent type=xfs capacity=1GiB name=x"); err == nil {
+		t.Errorf("TestParse: expected an error for the wrong command")
+	}
+
+	// The types other than bool, string, and list-of-string have no meaning without a value
+	for _, arg := range []string{"count", "timeout", "ratio"} {
+		_, err := Parse(spec, "#DW jobdw type=xfs capacity=1GiB name=x "+arg)
+		if err == nil || err.Error() != fmt.Sprintf("argument '%s' requires a value", arg) {
+			t.Errorf("TestParse: expected a missing value error for '%s', got %v", arg, err)
+		}
+	}
+}
+
+func TestDefaultsAndConstraints(t *testing.T) {
+	spec := DWDirectiveRuleSpec{
+		Command: "jobdw",
+		RuleDefs: []DWDirectiveRuleDef{
+			{Key: "type", Type: RuleTypeEnum, Enum: []string{"xfs", "lustre"}, Default: "xfs"},
+			{Key: "capacity", Type: RuleTypeSize},
+			{Key: "profile", Type: RuleTypeString},
+			{Key: "requires", Type: RuleTypeListOfString},
+			{Key: "user", Type: RuleTypeString},
+			{Key: "group", Type: RuleTypeString},
+		},
+		Constraints: []DWDirectiveRuleConstraint{
+			{Type: ConstraintMutuallyExclusive, Keys: []string{"profile", "capacity"}},
+			{Type: ConstraintCoRequired, Keys: []string{"user", "group"}},
+			{
+				Type: ConstraintConditional,
+				If:   &DWDirectiveArgCondition{Key: "requires", Value: "copy-offload"},
+				Then: &DWDirectiveArgCondition{Key: "type", Value: "lustre"},
+			},
+		},
+	}
+
+	directive, err := Parse(spec, "#DW jobdw capacity=1GiB")
+	if err != nil {
+		t.Fatalf("TestDefaultsAndConstraints: unexpected error: %v", err)
+	}
+	if value, _ := directive.String("type"); value != "xfs" {
+		t.Errorf("TestDefaultsAndConstraints: expected default type xfs, got '%s'", value)
+	}
+
+	defaults, err := Defaults(spec, "#DW jobdw capacity=1GiB")
+	if err != nil || !reflect.DeepEqual(defaults, map[string]string{"type": "xfs"}) {
+		t.Errorf("TestDefaultsAndConstraints: expected default type xfs, got %v, err(%v)", defaults, err)
+	}
+
+	defaults, err = Defaults(spec, "#DW jobdw type=lustre")
+	if err != nil || len(defaults) != 0 {
+		t.Errorf("TestDefaultsAndConstraints: expected no defaults, got %v, err(%v)", defaults, err)
+	}
+
+	tests := []testCase{
+		{directives: []string{"#DW jobdw profile=small"}, result: pass},
+		{directives: []string{"#DW jobdw profile=small capacity=1GiB"}, result: fail},
+		{directives: []string{"#DW jobdw user=a group=b"}, result: pass},
+		{directives: []string{"#DW jobdw user=a"}, result: fail},
+		{directives: []string{"#DW jobdw type=lustre requires=other,copy-offload"}, result: pass},
+		{directives: []string{"#DW jobdw requires=copy-offload"}, result: fail}, // type defaults to xfs
+		{directives: []string{"#DW jobdw requires=other"}, result: pass},
+	}
+
+	test(t, []DWDirectiveRuleSpec{spec}, tests)
+
+	badDefault := []DWDirectiveRuleSpec{{
+		Command:  "bad",
+		RuleDefs: []DWDirectiveRuleDef{{Key: "type", Type: RuleTypeEnum, Enum: []string{"xfs"}, Default: "gfs2"}},
+	}}
+	test(t, badDefault, []testCase{
+		{directives: []string{"#DW bad type=xfs"}, result: pass},
+		{directives: []string{"#DW bad"}, result: fail},
+	})
+
+	// A default is checked for uniqueness along with the values in the directives
+	uniqueDefault := []DWDirectiveRuleSpec{{
+		Command:  "unique",
+		RuleDefs: []DWDirectiveRuleDef{{Key: "name", Type: RuleTypeString, UniqueWithin: "names", Default: "scratch"}},
+	}}
+	test(t, uniqueDefault, []testCase{
+		{directives: []string{"#DW unique", "#DW unique name=other"}, result: pass},
+		{directives: []string{"#DW unique", "#DW unique"}, result: fail},
+		{directives: []string{"#DW unique name=scratch", "#DW unique"}, result: fail},
+		{directives: []string{"#DW unique", "#DW unique name=scratch"}, result: fail},
+	})
+}
@@ -1,5 +1,5 @@
 /*
- * Copyright 2021-2024 Hewlett Packard Enterprise Development LP
+ * Copyright 2021-2025 Hewlett Packard Enterprise Development LP
  * Other additional copyright holders may be indicated within.
  *
  * The entirety of this work is licensed under the Apache License,
@@ -22,8 +22,8 @@ package dwdparse
 import (
 	"fmt"
 	"regexp"
-	"strconv"
 	"strings"
+	"unicode"
 )
 
 // DWDirectiveRuleDef defines the DWDirective parser rules
@@ -38,8 +38,35 @@ type DWDirectiveRuleDef struct {
 	IsRequired      bool     `json:"isRequired,omitempty"`
 	IsValueRequired bool     `json:"isValueRequired,omitempty"`
 	UniqueWithin    string   `json:"uniqueWithin,omitempty"`
+
+	// MinValue and MaxValue are the limits for the size, duration and float types,
+	// written the same way as the argument's value. For example, "1GiB" or "30s".
+	MinValue string `json:"minValue,omitempty"`
+	MaxValue string `json:"maxValue,omitempty"`
+
+	// Enum is the list of values allowed for the enum type
+	Enum []string `json:"enum,omitempty"`
+
+	// Default is the value used for the argument when a directive doesn't have it. The
+	// Key must be the argument's literal name for a default to be used. A default is
+	// subject to UniqueWithin like any other value.
+	Default string `json:"default,omitempty"`
 }
 
+// Types of DWDirectiveRuleDef. A bool value is checked with strconv.ParseBool, and a bool
+// argument without a value is true. This is a breaking change for bool rules without a
+// Pattern, which used to accept any value: values such as "yes" and "on" are now rejected.
+const (
+	RuleTypeInteger      = "integer"
+	RuleTypeBool         = "bool"
+	RuleTypeString       = "string"
+	RuleTypeListOfString = "list-of-string"
+	RuleTypeSize         = "size"
+	RuleTypeDuration     = "duration"
+	RuleTypeFloat        = "float"
+	RuleTypeEnum         = "enum"
+)
+
 const emptyValue = "$$empty_value_123456$$"
 
 // DWDirectiveRuleSpec defines the desired state of DWDirective
@@ -57,38 +84,104 @@ type DWDirectiveRuleSpec struct {
 	// in the Workflow resource
 	WatchStates string `json:"watchStates,omitempty"`
 
+	// List of driver labels that must complete their work in a watch state
+	// before the driver for this rule may start its work in that state. Only
+	// the drivers registered for the same state of the same workflow are
+	// considered.
+	DependsOn []string `json:"dependsOn,omitempty"`
+
 	// List of key/value pairs this #DW command is expected to have
 	RuleDefs []DWDirectiveRuleDef `json:"ruleDefs"`
+
+	// List of relationships between the arguments that a directive must satisfy
+	Constraints []DWDirectiveRuleConstraint `json:"constraints,omitempty"`
+}
+
+// Types of DWDirectiveRuleConstraint
+const (
+	// ConstraintMutuallyExclusive allows at most one of the constraint's keys in a directive
+	ConstraintMutuallyExclusive = "MutuallyExclusive"
+
+	// ConstraintCoRequired requires all of the constraint's keys once any one of them is
+	// in a directive
+	ConstraintCoRequired = "CoRequired"
+
+	// ConstraintConditional requires a directive that matches the If condition to match
+	// the Then condition too
+	ConstraintConditional = "Conditional"
+)
+
+// DWDirectiveRuleConstraint describes a relationship between the arguments of a directive.
+// MutuallyExclusive and CoRequired constraints are checked against the arguments given in
+// the directive. Conditional constraints are checked after the defaults have been applied.
+// +kubebuilder:object:generate=true
+type DWDirectiveRuleConstraint struct {
+	// Type of the constraint
+	// +kubebuilder:validation:Enum=MutuallyExclusive;CoRequired;Conditional
+	Type string `json:"type"`
+
+	// Keys are the arguments of a MutuallyExclusive or CoRequired constraint
+	Keys []string `json:"keys,omitempty"`
+
+	// If is the condition that makes a Conditional constraint apply
+	If *DWDirectiveArgCondition `json:"if,omitempty"`
+
+	// Then is the condition a directive must meet when the If condition is met
+	Then *DWDirectiveArgCondition `json:"then,omitempty"`
+}
+
+// DWDirectiveArgCondition matches a directive that has an argument, optionally with a
+// particular value. A list-of-string argument matches when any of its words is the value.
+// +kubebuilder:object:generate=true
+type DWDirectiveArgCondition struct {
+	// Key of the argument
+	Key string `json:"key"`
+
+	// Value the argument must have. An empty value matches any value.
+	Value string `json:"value,omitempty"`
 }
 
-// BuildArgsMap builds a map of the DWDirective's arguments in the form: args["key"] = value
+// BuildArgsMap builds a map of the DWDirective's arguments in the form: args["key"] = value.
+// A problem with the directive is returned as a *ValidationError. An argument without a
+// value is given a placeholder value that is only meaningful to ValidateArgs; drivers that
+// want the argument values should use Parse instead.
+//
+// Arguments are separated by whitespace. A value that contains whitespace can be quoted
+// the way a shell would quote it: single quotes preserve everything up to the closing
+// quote, double quotes allow '\"' and '\\' escapes, and a backslash outside of quotes
+// escapes the next character. For example, stage_in source="/lus/my dir/file" is a single
+// argument.
 func BuildArgsMap(dwd string) (map[string]string, error) {
 
-	dwdArgs := strings.Fields(dwd)
+	dwdArgs, err := splitDirective(dwd)
+	if err != nil {
+		return nil, newValidationError(ReasonParseError, "", "", "%s", err.Error())
+	}
+
+	if len(dwdArgs) == 0 || dwdArgs[0].text != "#DW" {
+		return nil, newValidationError(ReasonMissingPrefix, "", "", "missing '#DW' prefix in directive '%s'", dwd)
+	}
 
-	if len(dwdArgs) == 0 || dwdArgs[0] != "#DW" {
-		return nil, fmt.Errorf("missing '#DW' prefix in directive '%s'", dwd)
+	if len(dwdArgs) == 1 {
+		return nil, newValidationError(ReasonMissingCommand, "", "", "missing command in directive '%s'", dwd)
 	}
 
 	argsMap := make(map[string]string)
-	argsMap["command"] = dwdArgs[1]
+	argsMap["command"] = dwdArgs[1].text
 	for i := 2; i < len(dwdArgs); i++ {
-		keyValue := strings.Split(dwdArgs[i], "=")
+		keyValue := strings.SplitN(dwdArgs[i].text, "=", 2)
 
 		// Don't allow repeated arguments
 		_, ok := argsMap[keyValue[0]]
 		if ok {
-			return nil, fmt.Errorf("repeated argument '%s' in directive '%s'", keyValue[0], dwd)
+			return nil, newValidationError(ReasonRepeatedArgument, keyValue[0], "", "repeated argument '%s' at column %d in directive '%s'", keyValue[0], dwdArgs[i].column, dwd)
 		}
 
 		if len(keyValue) == 1 {
 			// We won't know how to interpret an empty value--whether it's allowed,
 			// or what its type should be--until ValidateArgs().
 			argsMap[keyValue[0]] = emptyValue
-		} else if len(keyValue) == 2 {
-			argsMap[keyValue[0]] = keyValue[1]
 		} else {
-			keyValue := strings.SplitN(dwdArgs[i], "=", 2)
 			argsMap[keyValue[0]] = keyValue[1]
 		}
 	}
@@ -96,201 +189,105 @@ func BuildArgsMap(dwd string) (map[string]string, error) {
 	return argsMap, nil
 }
 
-// Compile this regex outside the loop for better performance.
-var boolMatcher = regexp.MustCompile(`(?i)^(true|false)$`) // (?i) -> case-insensitve comparison
-
-// ValidateArgs validates a map of arguments against the rule specification
-func ValidateArgs(spec DWDirectiveRuleSpec, args map[string]string, uniqueMap map[string]bool) error {
-
-	command, found := args["command"]
-	if !found {
-		return fmt.Errorf("no command in arguments")
-	}
-
-	if command != spec.Command {
-		return fmt.Errorf("command '%s' does not match rule '%s'", command, spec.Command)
-	}
-
-	// Create a map that maps a directive rule definition to an argument that correctly matches it
-	// key: DWDirectiveRule	value: argument that matches that rule
-	// Required to check that all DWDirectiveRuleDef's have been met
-	argToRuleMap := map[*DWDirectiveRuleDef]string{}
+// directiveArg is one whitespace separated argument of a directive, with its quoting
+// removed. Column is the 1-based column where the argument starts.
+type directiveArg struct {
+	text   string
+	column int
+}
 
-	findRuleDefinition := func(key string) (*DWDirectiveRuleDef, error) {
-		for index, rule := range spec.RuleDefs {
-			re, err := regexp.Compile(rule.Key)
-			if err != nil {
-				return nil, fmt.Errorf("invalid rule regular expression '%s'", rule.Key)
-			}
+// splitDirective splits a directive into its arguments, honoring quotes and escapes
+func splitDirective(dwd string) ([]directiveArg, error) {
+	args := []directiveArg{}
 
-			if re.MatchString(key) {
-				return &spec.RuleDefs[index], nil
-			}
-		}
+	var text strings.Builder
+	inArg := false
+	argColumn := 0
+	quote := rune(0)
+	quoteColumn := 0
+	escaped := false
 
-		return nil, fmt.Errorf("unsupported argument '%s'", key)
-	}
+	column := 0
+	for _, r := range dwd {
+		column++
 
-	// Iterate over all arguments and validate each based on the associated rule
-	for k, v := range args {
-		if k == "command" {
+		if !inArg && quote == 0 && !escaped && unicode.IsSpace(r) {
 			continue
 		}
 
-		rule, err := findRuleDefinition(k)
-		if err != nil {
-			return err
+		if !inArg {
+			inArg = true
+			argColumn = column
 		}
 
-		if v == emptyValue {
-			if rule.Type == "bool" && !rule.IsValueRequired {
-				// Booleans default to true.
-				v = "true"
-			} else if rule.IsValueRequired {
-				return fmt.Errorf("argument '%s' requires value", k)
+		switch {
+		case escaped:
+			// Inside double quotes only a quote or a backslash may be escaped; the
+			// backslash is kept for anything else, as a shell would do.
+			if quote == '"' && r != '"' && r != '\\' {
+				text.WriteRune('\\')
 			}
-		}
-
-		switch rule.Type {
-		case "integer":
-			i, err := strconv.Atoi(v)
-			if err != nil {
-				return fmt.Errorf("argument '%s' invalid integer '%s'", k, v)
-			}
-			if rule.Max != 0 && i > rule.Max {
-				return fmt.Errorf("argument '%s' specified integer value %d is greather than maximum value %d", k, i, rule.Max)
-			}
-			if rule.Min != 0 && i < rule.Min {
-				return fmt.Errorf("argument '%s' specified integer value %d is less than minimum value %d", k, i, rule.Min)
-			}
-		case "bool":
-			if rule.Pattern != "" {
-				if !boolMatcher.MatchString(v) {
-					return fmt.Errorf("argument '%s' invalid boolean '%s'", k, v)
-				}
+			text.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				text.WriteRune(r)
 			}
-		case "string":
-			if rule.Pattern != "" {
-				re, err := regexp.Compile(rule.Pattern)
-				if err != nil {
-					return fmt.Errorf("invalid regular expression '%s'", rule.Pattern)
-				}
-
-				if !re.MatchString(v) {
-					return fmt.Errorf("argument '%s' invalid string '%s'", k, v)
-				}
-			}
-		case "list-of-string":
-			words := strings.Split(v, ",")
-			if len(rule.Patterns) > 0 {
-				wordsMatched := make(map[string]bool)
-				for _, word := range words {
-					for idx := range rule.Patterns {
-						re, err := regexp.Compile(rule.Patterns[idx])
-						if err != nil {
-							return fmt.Errorf("invalid regular expression '%s'", rule.Patterns[idx])
-						}
-
-						if re.MatchString(word) {
-							wordsMatched[word] = true
-							break
-						}
-					}
-					if !wordsMatched[word] {
-						return fmt.Errorf("argument '%s' invalid string '%s' in list '%s'", k, word, v)
-					}
-				}
-			}
-			// All of the words in the list are valid, but were any words repeated?
-			wordMap := make(map[string]bool)
-			for _, word := range words {
-				if _, present := wordMap[word]; present {
-					return fmt.Errorf("argument '%s' word '%s' repeated in list '%s'", k, word, v)
-				}
-				wordMap[word] = true
+		case r == '\\':
+			escaped = true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				text.WriteRune(r)
 			}
+		case r == '\'' || r == '"':
+			quote = r
+			quoteColumn = column
+		case unicode.IsSpace(r):
+			args = append(args, directiveArg{text: text.String(), column: argColumn})
+			text.Reset()
+			inArg = false
 		default:
-			return fmt.Errorf("unsupported rule type '%s'", rule.Type)
+			text.WriteRune(r)
 		}
+	}
 
-		if rule.UniqueWithin != "" {
-			_, ok := uniqueMap[rule.UniqueWithin+"/"+v]
-			if ok {
-				return fmt.Errorf("value '%s' must be unique within '%s'", v, rule.UniqueWithin)
-			}
-
-			uniqueMap[rule.UniqueWithin+"/"+v] = true
-		}
+	if escaped {
+		return nil, fmt.Errorf("unfinished escape at column %d in directive '%s'", column, dwd)
+	}
 
-		// NOTE: We know that we don't have repeated arguments here because the arguments
-		//       come to us in a map indexed by the argment name.
-		argToRuleMap[rule] = k
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote starting at column %d in directive '%s'", quote, quoteColumn, dwd)
 	}
 
-	// Iterate over the rules to ensure all required rules have an argument
-	for index := range spec.RuleDefs {
-		rule := &spec.RuleDefs[index]
-		if rule.IsRequired {
-			if _, found := argToRuleMap[rule]; !found {
-				return fmt.Errorf("missing required argument '%v'", rule.Key)
-			}
-		}
+	if inArg {
+		args = append(args, directiveArg{text: text.String(), column: argColumn})
 	}
 
-	return nil
+	return args, nil
 }
 
-// Validate a list of directives against the supplied rules. When a directive is valid
-// for a particular rule, the `onValidDirectiveFunc` function is called.
-func Validate(rules []DWDirectiveRuleSpec, directives []string, onValidDirectiveFunc func(index int, rule DWDirectiveRuleSpec)) error {
-
-	// Create a map to track argument uniqueness within the directives for
-	// rules that contain `UniqueWithin`
-	uniqueMap := make(map[string]bool)
-
-	for index, directive := range directives {
-
-		// A directive is validated against all rules; any one rule that is valid for a directive
-		// makes that directive valid.
-		validDirective := false
-
-		for _, rule := range rules {
-			valid, err := validateDWDirective(rule, directive, uniqueMap)
-			if err != nil {
-				return err
-			}
-
-			if valid {
-				validDirective = true
-				onValidDirectiveFunc(index, rule)
-			}
-		}
+// Compile this regex outside the loop for better performance.
+var boolMatcher = regexp.MustCompile(`(?i)^(true|false)$`) // (?i) -> case-insensitve comparison
 
-		if !validDirective {
-			return fmt.Errorf("invalid directive '%s'", directive)
-		}
+// ValidateArgs validates a map of arguments against the rule specification. All the problems
+// with the arguments are returned together as ValidationErrors. The rule's regular expressions
+// are compiled on each call; use a RuleSet to validate many directives against the same rules.
+func ValidateArgs(spec DWDirectiveRuleSpec, args map[string]string, uniqueMap map[string]bool) error {
+	rule := compileRule(spec)
+	if errs := rule.validateArgs(args, uniqueMap); len(errs) > 0 {
+		return errs
 	}
 
 	return nil
 }
 
-// validateDWDirective validates an individual directive against a rule
-func validateDWDirective(rule DWDirectiveRuleSpec, dwd string, uniqueMap map[string]bool) (bool, error) {
-
-	// Build a map of the #DW commands and arguments
-	argsMap, err := BuildArgsMap(dwd)
-	if err != nil {
-		return false, err
-	}
-
-	if argsMap["command"] != rule.Command {
-		return false, nil
-	}
-
-	err = ValidateArgs(rule, argsMap, uniqueMap)
-	if err != nil {
-		return false, err
-	}
-
-	return true, nil
+// Validate a list of directives against the supplied rules. When a directive is valid
+// for a particular rule, the `onValidDirectiveFunc` function is called. The problems
+// found in all the directives are returned together as ValidationErrors.
+func Validate(rules []DWDirectiveRuleSpec, directives []string, onValidDirectiveFunc func(index int, rule DWDirectiveRuleSpec)) error {
+	return NewRuleSet(rules).Validate(directives, onValidDirectiveFunc)
 }
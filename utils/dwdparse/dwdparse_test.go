@@ -20,6 +20,8 @@
 package dwdparse
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -72,6 +74,12 @@ var dWDRules = []DWDirectiveRuleSpec{
 				IsRequired:      false,
 				IsValueRequired: false,
 			},
+			{
+				Key:             "boolcheck3",
+				Type:            "bool",
+				IsRequired:      false,
+				IsValueRequired: true,
+			},
 		},
 	},
 	{
@@ -257,6 +265,8 @@ var dwDirectiveTests = []struct {
 	{[]string{"#DW jobdw type=lustre capacity=100GB name=Bool4 boolcheck1=true"}, pass},
 	{[]string{"#DW jobdw type=lustre capacity=100GB name=Bool5 boolcheck1=0"}, fail},
 	{[]string{"#DW jobdw type=lustre capacity=100GB name=Bool6 boolcheck1=hello"}, fail},
+	{[]string{"#DW jobdw type=lustre capacity=100GB name=Bool7 boolcheck3=1"}, pass},
+	{[]string{"#DW jobdw type=lustre capacity=100GB name=Bool8 boolcheck3=yes"}, fail},
 
 	{[]string{"#DW create_persistent type=raw    capacity=100GB name=prettyGoodName  "}, pass},
 	{[]string{"#DW create_persistent type=xfs    capacity=100GB name=prettyGoodName  "}, pass},
@@ -476,6 +486,230 @@ func TestKeyMalformedRegexp(t *testing.T) {
 	test(t, rules, tests)
 }
 
+func TestQuotedArguments(t *testing.T) {
+	tests := []struct {
+		directive string
+		args      map[string]string
+		err       string
+	}{
+		{
+			directive: `#DW copy_in source="/lus/my dir/file" destination=$DW_JOB_x`,
+			args:      map[string]string{"command": "copy_in", "source": "/lus/my dir/file", "destination": "$DW_JOB_x"},
+		},
+		{
+			directive: `#DW copy_in 'source=/lus/it"s here' description='a "quoted" word'`,
+			args:      map[string]string{"command": "copy_in", "source": `/lus/it"s here`, "description": `a "quoted" word`},
+		},
+		{
+			directive: `#DW copy_in source=/lus/my\ dir/file description="say \"hi\" \\ \n"`,
+			args:      map[string]string{"command": "copy_in", "source": "/lus/my dir/file", "description": `say "hi" \ \n`},
+		},
+		{
+			directive: `#DW copy_in source="" flag key=a=b`,
+			args:      map[string]string{"command": "copy_in", "source": "", "flag": emptyValue, "key": "a=b"},
+		},
+		{
+			directive: `#DW copy_in source="/lus/my dir`,
+			err:       "column 20",
+		},
+		{
+			directive: `#DW copy_in source='/lus/my dir`,
+			err:       "column 20",
+		},
+		{
+			directive: `#DW copy_in source=/lus/dir\`,
+			err:       "column 28",
+		},
+		{
+			directive: `#DW copy_in source=a "source=b c"`,
+			err:       "column 22",
+		},
+		{
+			directive: `#DW`,
+			err:       "missing command",
+		},
+	}
+
+	for index, tt := range tests {
+		args, err := BuildArgsMap(tt.directive)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("TestQuotedArguments(%d): expected error containing '%s', got '%v'", index, tt.err, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("TestQuotedArguments(%d): unexpected error: %v", index, err)
+		} else if !reflect.DeepEqual(args, tt.args) {
+			t.Errorf("TestQuotedArguments(%d): expected %v, got %v", index, tt.args, args)
+		}
+	}
+}
+
+func TestQuotedRuleValidation(t *testing.T) {
+	rules := []DWDirectiveRuleSpec{{
+		Command: "copy_in",
+		RuleDefs: []DWDirectiveRuleDef{
+			{
+				Key:             "source",
+				Type:            "string",
+				Pattern:         "^/lus/.+$",
+				IsRequired:      true,
+				IsValueRequired: true,
+			},
+			{
+				Key:  "description",
+				Type: "string",
+			},
+		},
+	}}
+
+	tests := []testCase{
+		{directives: []string{`#DW copy_in source="/lus/my dir/file" description='stage the inputs'`}, result: pass},
+		{directives: []string{`#DW copy_in source='/scratch/my dir'`}, result: fail},
+		{directives: []string{`#DW copy_in source="/lus/my dir`}, result: fail},
+	}
+
+	test(t, rules, tests)
+}
+
+func TestRuleSet(t *testing.T) {
+	ruleSet := NewRuleSet(dWDRules)
+
+	if !reflect.DeepEqual(ruleSet.Rules(), dWDRules) {
+		t.Errorf("TestRuleSet: expected rules %v, got %v", dWDRules, ruleSet.Rules())
+	}
+
+	// The same compiled rules give the same results as validating with the raw rules
+	for index, tt := range dwDirectiveTests {
+		err := ruleSet.Validate(tt.directiveList, func(int, DWDirectiveRuleSpec) {})
+
+		if (tt.result == pass && err != nil) || (tt.result == fail && err == nil) {
+			t.Errorf("TestRuleSet(%s)(%d): expect_valid(%v) err(%v)", tt.directiveList, index, tt.result, err)
+		}
+	}
+}
+
+func TestMergeRuleSets(t *testing.T) {
+	ruleSet := MergeRuleSets(NewRuleSet(dWDRules[:1]), NewRuleSet(dWDRules[1:]))
+
+	if !reflect.DeepEqual(ruleSet.Rules(), dWDRules) {
+		t.Errorf("TestMergeRuleSets: expected rules %v, got %v", dWDRules, ruleSet.Rules())
+	}
+
+	for index, tt := range dwDirectiveTests {
+		err := ruleSet.Validate(tt.directiveList, func(int, DWDirectiveRuleSpec) {})
+
+		if (tt.result == pass && err != nil) || (tt.result == fail && err == nil) {
+			t.Errorf("TestMergeRuleSets(%s)(%d): expect_valid(%v) err(%v)", tt.directiveList, index, tt.result, err)
+		}
+	}
+}
+
+func TestValidationErrors(t *testing.T) {
+	directives := []string{
+		"#DW jobdw type=xfs capacity=10GiB name=good",
+		"#DW jobdw type=none capacity=10 profile=x bogus=1",
+		"#DW unknown_command arg=1",
+		`#DW jobdw type="xfs`,
+	}
+
+	err := Validate(dWDRules, directives, func(int, DWDirectiveRuleSpec) {})
+
+	errs, ok := err.(ValidationErrors)
+	if !ok {
+		t.Fatalf("TestValidationErrors: expected ValidationErrors, got %T: %v", err, err)
+	}
+
+	expected := []ValidationError{
+		{Index: 1, Key: "bogus", Reason: ReasonUnsupportedArgument},
+		{Index: 1, Key: "capacity", RuleKey: "capacity", Reason: ReasonInvalidValue},
+		{Index: 1, Key: "profile", RuleKey: "profile", Reason: ReasonInvalidValue},
+		{Index: 1, Key: "type", RuleKey: "type", Reason: ReasonInvalidValue},
+		{Index: 1, RuleKey: "name", Reason: ReasonMissingArgument},
+		{Index: 2, Reason: ReasonInvalidDirective},
+		{Index: 3, Reason: ReasonParseError},
+	}
+
+	if len(errs) != len(expected) {
+		t.Fatalf("TestValidationErrors: expected %d errors, got %d: %v", len(expected), len(errs), errs)
+	}
+
+	for i, e := range expected {
+		got := errs[i]
+		if got.Index != e.Index || got.Key != e.Key || got.RuleKey != e.RuleKey || got.Reason != e.Reason {
+			t.Errorf("TestValidationErrors(%d): expected %+v, got %+v", i, e, *got)
+		}
+	}
+}
+
+func BenchmarkRuleSetValidate(b *testing.B) {
+	ruleSet := NewRuleSet(dWDRules)
+	directives := []string{"#DW jobdw type=xfs capacity=10GiB name=bench profile=my-profile"}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := ruleSet.Validate(directives, func(int, DWDirectiveRuleSpec) {}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 // Just touch ginkgo, so it's here to interpret any ginkgo args from
 // "make test", so that doesn't fail on this test file.
 var _ = BeforeSuite(func() {})
+
+func TestCheckRule(t *testing.T) {
+	for index, rule := range dWDRules {
+		if problems := CheckRule(rule); len(problems) != 0 {
+			t.Errorf("TestCheckRule(%d): expected no problems, got %v", index, problems)
+		}
+	}
+
+	rule := DWDirectiveRuleSpec{
+		Command:     "check",
+		DriverLabel: "me",
+		DependsOn:   []string{"me"},
+		RuleDefs: []DWDirectiveRuleDef{
+			{Key: "*", Type: RuleTypeString},
+			{Key: "pattern", Type: RuleTypeString, Pattern: "^[a-"},
+			{Key: "type", Type: "text"},
+			{Key: "count", Type: RuleTypeInteger, Min: 10, Max: 1},
+			{Key: "capacity", Type: RuleTypeSize, MinValue: "10GiB", MaxValue: "1GiB"},
+			{Key: "mode", Type: RuleTypeEnum},
+			{Key: "name", Type: RuleTypeString, MinValue: "a"},
+			{Key: "name", Type: RuleTypeEnum, Enum: []string{"a"}, Default: "b"},
+		},
+		Constraints: []DWDirectiveRuleConstraint{
+			{Type: ConstraintMutuallyExclusive, Keys: []string{"count"}},
+			{Type: ConstraintConditional, If: &DWDirectiveArgCondition{Key: "count"}},
+			{Type: "Sometimes"},
+		},
+	}
+
+	fields := map[string]bool{}
+	for _, problem := range CheckRule(rule) {
+		fields[problem.Field] = true
+	}
+
+	for _, field := range []string{
+		"dependsOn[0]",
+		"ruleDefs[0].key",
+		"ruleDefs[1].pattern",
+		"ruleDefs[2].type",
+		"ruleDefs[3].min",
+		"ruleDefs[4].minValue",
+		"ruleDefs[5].enum",
+		"ruleDefs[6].minValue",
+		"ruleDefs[7].key",
+		"ruleDefs[7].default",
+		"constraints[0].keys",
+		"constraints[1].then",
+		"constraints[2].type",
+	} {
+		if !fields[field] {
+			t.Errorf("TestCheckRule: expected a problem with %s, got %v", field, fields)
+		}
+	}
+}
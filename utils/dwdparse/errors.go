@@ -0,0 +1,130 @@
+/*
+ * Copyright 2025 Hewlett Packard Enterprise Development LP
+ * Other additional copyright holders may be indicated within.
+ *
+ * The entirety of this work is licensed under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ *
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dwdparse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationReason is a machine readable code for a directive validation problem
+type ValidationReason string
+
+const (
+	// ReasonParseError means the directive couldn't be split into arguments
+	ReasonParseError ValidationReason = "ParseError"
+
+	// ReasonMissingPrefix means the directive doesn't start with #DW
+	ReasonMissingPrefix ValidationReason = "MissingPrefix"
+
+	// ReasonMissingCommand means the directive has no command after the #DW prefix
+	ReasonMissingCommand ValidationReason = "MissingCommand"
+
+	// ReasonRepeatedArgument means an argument was given more than once
+	ReasonRepeatedArgument ValidationReason = "RepeatedArgument"
+
+	// ReasonInvalidDirective means no rule matches the directive's command
+	ReasonInvalidDirective ValidationReason = "InvalidDirective"
+
+	// ReasonUnsupportedArgument means no rule definition matches the argument's key
+	ReasonUnsupportedArgument ValidationReason = "UnsupportedArgument"
+
+	// ReasonValueRequired means the argument was given without a value
+	ReasonValueRequired ValidationReason = "ValueRequired"
+
+	// ReasonInvalidValue means the argument's value isn't valid for its type or pattern
+	ReasonInvalidValue ValidationReason = "InvalidValue"
+
+	// ReasonOutOfRange means the argument's value is outside the rule's minimum or maximum
+	ReasonOutOfRange ValidationReason = "OutOfRange"
+
+	// ReasonRepeatedWord means a word appears more than once in a list-of-string value
+	ReasonRepeatedWord ValidationReason = "RepeatedWord"
+
+	// ReasonNotUnique means the value is already used by another directive in the same
+	// UniqueWithin group
+	ReasonNotUnique ValidationReason = "NotUnique"
+
+	// ReasonMissingArgument means a required argument wasn't given
+	ReasonMissingArgument ValidationReason = "MissingArgument"
+
+	// ReasonConstraint means the arguments don't satisfy one of the rule's constraints
+	ReasonConstraint ValidationReason = "Constraint"
+
+	// ReasonInvalidRule means the rule itself is broken, such as a regular expression that
+	// doesn't compile or an unknown type
+	ReasonInvalidRule ValidationReason = "InvalidRule"
+)
+
+// ValidationError describes one problem found while validating a directive
+type ValidationError struct {
+	// Index of the directive in the list passed to Validate, or -1 when the directive
+	// was validated on its own
+	Index int
+
+	// Key of the directive argument with the problem, if any
+	Key string
+
+	// RuleKey is the Key of the rule definition involved, if any
+	RuleKey string
+
+	// Reason is a code for the problem
+	Reason ValidationReason
+
+	// Message describes the problem
+	Message string
+}
+
+// Error returns the message describing the problem
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// newValidationError returns a ValidationError that isn't tied to a directive index yet
+func newValidationError(reason ValidationReason, key string, ruleKey string, format string, a ...interface{}) *ValidationError {
+	return &ValidationError{
+		Index:   -1,
+		Key:     key,
+		RuleKey: ruleKey,
+		Reason:  reason,
+		Message: fmt.Sprintf(format, a...),
+	}
+}
+
+// ValidationErrors is the list of problems found while validating directives. It is
+// returned as an error by Validate and ValidateArgs when there is at least one problem.
+type ValidationErrors []*ValidationError
+
+// Error joins the messages of all the problems
+func (e ValidationErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// setIndex ties each of the problems to a directive index
+func (e ValidationErrors) setIndex(index int) {
+	for _, err := range e {
+		err.Index = index
+	}
+}
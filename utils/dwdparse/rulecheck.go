@@ -0,0 +1,158 @@
+/*
+ * Copyright 2025 Hewlett Packard Enterprise Development LP
+ * Other additional copyright holders may be indicated within.
+ *
+ * The entirety of this work is licensed under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ *
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dwdparse
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// RuleProblem describes something wrong with a DWDirectiveRuleSpec that would keep it
+// from validating directives
+type RuleProblem struct {
+	// Field is the path of the field with the problem, relative to the rule spec. For
+	// example, "ruleDefs[1].pattern".
+	Field string
+
+	// Value of the field
+	Value interface{}
+
+	// Message describes the problem
+	Message string
+}
+
+// CheckRule looks for problems in a rule: regular expressions that don't compile, unknown
+// types, limits and defaults that don't fit the type, and constraints or dependencies that
+// can't be met. The rule's DriverLabel should be set to the label the rule will be used
+// with so a rule that depends on itself can be found.
+func CheckRule(spec DWDirectiveRuleSpec) []RuleProblem {
+	problems := []RuleProblem{}
+	add := func(field string, value interface{}, format string, a ...interface{}) {
+		problems = append(problems, RuleProblem{Field: field, Value: value, Message: fmt.Sprintf(format, a...)})
+	}
+
+	if spec.Command == "" {
+		add("command", spec.Command, "a command is required")
+	}
+
+	dependsOn := map[string]bool{}
+	for i, label := range spec.DependsOn {
+		field := fmt.Sprintf("dependsOn[%d]", i)
+		switch {
+		case label == "":
+			add(field, label, "a driver label is required")
+		case label == spec.DriverLabel:
+			add(field, label, "the rule can't depend on its own driver label")
+		case dependsOn[label]:
+			add(field, label, "duplicate driver label")
+		}
+		dependsOn[label] = true
+	}
+
+	rule := compileRule(spec)
+	keys := map[string]bool{}
+	for i := range rule.defs {
+		compiled := &rule.defs[i]
+		def := compiled.def
+		field := func(name string) string { return fmt.Sprintf("ruleDefs[%d].%s", i, name) }
+
+		if def.Key == "" {
+			add(field("key"), def.Key, "a key is required")
+		} else if compiled.keyErr != nil {
+			add(field("key"), def.Key, "%s", compiled.keyErr.Message)
+		} else if keys[def.Key] {
+			add(field("key"), def.Key, "duplicate key")
+		}
+		keys[def.Key] = true
+
+		if compiled.patternErr != nil {
+			add(field("pattern"), def.Pattern, "%s", compiled.patternErr.Message)
+		}
+		if compiled.patternsErr != nil {
+			add(field("patterns"), def.Patterns, "%s", compiled.patternsErr.Message)
+		}
+
+		switch def.Type {
+		case RuleTypeInteger:
+			if def.Min != 0 && def.Max != 0 && def.Min > def.Max {
+				add(field("min"), def.Min, "min %d is greater than max %d", def.Min, def.Max)
+			}
+		case RuleTypeSize, RuleTypeDuration, RuleTypeFloat:
+			if compiled.limitsErr != nil {
+				add(field("minValue"), def.MinValue, "%s", compiled.limitsErr.Message)
+			}
+		case RuleTypeEnum:
+			if compiled.limitsErr != nil {
+				add(field("enum"), def.Enum, "%s", compiled.limitsErr.Message)
+			}
+		case RuleTypeBool, RuleTypeString, RuleTypeListOfString:
+		default:
+			add(field("type"), def.Type, "unsupported rule type")
+		}
+
+		if (def.MinValue != "" || def.MaxValue != "") && def.Type != RuleTypeSize && def.Type != RuleTypeDuration && def.Type != RuleTypeFloat {
+			add(field("minValue"), def.MinValue, "minValue and maxValue may only be used with the %s, %s and %s types", RuleTypeSize, RuleTypeDuration, RuleTypeFloat)
+		}
+
+		if def.Default != "" {
+			if regexp.QuoteMeta(def.Key) != def.Key {
+				add(field("default"), def.Default, "a default may only be given for a literal key")
+			} else if compiled.defaultErr != nil {
+				add(field("default"), def.Default, "%s", compiled.defaultErr.Message)
+			}
+		}
+	}
+
+	// Each key in a constraint must be one of the rule's arguments
+	checkKey := func(field string, key string) {
+		if _, err := rule.findRuleDefinition(key); err != nil || key == "" {
+			add(field, key, "key doesn't match any of the rule's definitions")
+		}
+	}
+
+	for i, constraint := range spec.Constraints {
+		field := func(name string) string { return fmt.Sprintf("constraints[%d].%s", i, name) }
+
+		switch constraint.Type {
+		case ConstraintMutuallyExclusive, ConstraintCoRequired:
+			if len(constraint.Keys) < 2 {
+				add(field("keys"), constraint.Keys, "at least two keys are required")
+			}
+			for j, key := range constraint.Keys {
+				checkKey(field(fmt.Sprintf("keys[%d]", j)), key)
+			}
+		case ConstraintConditional:
+			if constraint.If == nil {
+				add(field("if"), nil, "a condition is required")
+			} else {
+				checkKey(field("if.key"), constraint.If.Key)
+			}
+			if constraint.Then == nil {
+				add(field("then"), nil, "a condition is required")
+			} else {
+				checkKey(field("then.key"), constraint.Then.Key)
+			}
+		default:
+			add(field("type"), constraint.Type, "unsupported constraint type")
+		}
+	}
+
+	return problems
+}
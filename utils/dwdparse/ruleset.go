@@ -0,0 +1,587 @@
+/*
+ * Copyright 2025 Hewlett Packard Enterprise Development LP
+ * Other additional copyright holders may be indicated within.
+ *
+ * The entirety of this work is licensed under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ *
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dwdparse
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RuleSet is a list of rules with their regular expressions compiled once, so the
+// same rules can be used to validate any number of directives. A RuleSet is not
+// modified after it is created and is safe for concurrent use.
+type RuleSet struct {
+	rules []compiledRule
+}
+
+// compiledRule is a DWDirectiveRuleSpec with the regular expressions for its
+// rule definitions
+type compiledRule struct {
+	spec DWDirectiveRuleSpec
+	defs []compiledRuleDef
+}
+
+// compiledRuleDef holds the compiled regular expressions of a DWDirectiveRuleDef. A
+// regular expression that doesn't compile is kept as an error, and is reported when
+// a directive needs it.
+type compiledRuleDef struct {
+	def *DWDirectiveRuleDef
+
+	key    *regexp.Regexp
+	keyErr *ValidationError
+
+	pattern    *regexp.Regexp
+	patternErr *ValidationError
+
+	patterns    []*regexp.Regexp
+	patternsErr *ValidationError
+
+	// minValue and maxValue are the parsed MinValue and MaxValue, if they were set
+	minValue  interface{}
+	maxValue  interface{}
+	limitsErr *ValidationError
+
+	// defaultErr is set when the Default isn't a valid value for the rule definition
+	defaultErr *ValidationError
+}
+
+// NewRuleSet compiles the regular expressions used by the rules
+func NewRuleSet(rules []DWDirectiveRuleSpec) *RuleSet {
+	ruleSet := &RuleSet{rules: make([]compiledRule, 0, len(rules))}
+	for _, rule := range rules {
+		ruleSet.rules = append(ruleSet.rules, compileRule(rule))
+	}
+
+	return ruleSet
+}
+
+// MergeRuleSets returns a RuleSet with the rules of each of the RuleSets, in order. The
+// rules aren't compiled again.
+func MergeRuleSets(ruleSets ...*RuleSet) *RuleSet {
+	merged := &RuleSet{}
+	for _, ruleSet := range ruleSets {
+		merged.rules = append(merged.rules, ruleSet.rules...)
+	}
+
+	return merged
+}
+
+// Rules returns the rules in the RuleSet
+func (rs *RuleSet) Rules() []DWDirectiveRuleSpec {
+	rules := make([]DWDirectiveRuleSpec, 0, len(rs.rules))
+	for _, rule := range rs.rules {
+		rules = append(rules, rule.spec)
+	}
+
+	return rules
+}
+
+// Validate a list of directives against the rules in the RuleSet. When a directive is
+// valid for a particular rule, the `onValidDirectiveFunc` function is called. Every directive
+// is checked, and the problems found in all of them are returned together as ValidationErrors.
+func (rs *RuleSet) Validate(directives []string, onValidDirectiveFunc func(index int, rule DWDirectiveRuleSpec)) error {
+
+	// Create a map to track argument uniqueness within the directives for
+	// rules that contain `UniqueWithin`
+	uniqueMap := make(map[string]bool)
+
+	errs := ValidationErrors{}
+	for index, directive := range directives {
+		directiveErrs := rs.validateDirective(index, directive, uniqueMap, onValidDirectiveFunc)
+		directiveErrs.setIndex(index)
+		errs = append(errs, directiveErrs...)
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// validateDirective validates a single directive against the rules that have the same command
+func (rs *RuleSet) validateDirective(index int, directive string, uniqueMap map[string]bool, onValidDirectiveFunc func(index int, rule DWDirectiveRuleSpec)) ValidationErrors {
+
+	// Build a map of the #DW commands and arguments
+	argsMap, err := BuildArgsMap(directive)
+	if err != nil {
+		return ValidationErrors{err.(*ValidationError)}
+	}
+
+	// A directive is validated against all rules for its command, and each of those
+	// rules must accept it.
+	errs := ValidationErrors{}
+	matchedRule := false
+	for i := range rs.rules {
+		rule := &rs.rules[i]
+		if argsMap["command"] != rule.spec.Command {
+			continue
+		}
+
+		matchedRule = true
+		if ruleErrs := rule.validateArgs(argsMap, uniqueMap); len(ruleErrs) > 0 {
+			errs = append(errs, ruleErrs...)
+			continue
+		}
+
+		onValidDirectiveFunc(index, rule.spec)
+	}
+
+	if !matchedRule {
+		return ValidationErrors{newValidationError(ReasonInvalidDirective, "", "", "invalid directive '%s'", directive)}
+	}
+
+	return errs
+}
+
+// compileRule compiles the regular expressions of each of the rule's definitions
+func compileRule(spec DWDirectiveRuleSpec) compiledRule {
+	rule := compiledRule{
+		spec: spec,
+		defs: make([]compiledRuleDef, len(spec.RuleDefs)),
+	}
+
+	for index := range spec.RuleDefs {
+		def := &spec.RuleDefs[index]
+		compiled := &rule.defs[index]
+		compiled.def = def
+
+		re, err := regexp.Compile(def.Key)
+		if err != nil {
+			compiled.keyErr = newValidationError(ReasonInvalidRule, "", def.Key, "invalid rule regular expression '%s'", def.Key)
+		}
+		compiled.key = re
+
+		if def.Pattern != "" {
+			re, err := regexp.Compile(def.Pattern)
+			if err != nil {
+				compiled.patternErr = newValidationError(ReasonInvalidRule, "", def.Key, "invalid regular expression '%s'", def.Pattern)
+			}
+			compiled.pattern = re
+		}
+
+		for _, pattern := range def.Patterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				compiled.patternsErr = newValidationError(ReasonInvalidRule, "", def.Key, "invalid regular expression '%s'", pattern)
+				break
+			}
+			compiled.patterns = append(compiled.patterns, re)
+		}
+
+		compiled.limitsErr = compiled.compileLimits()
+
+		if def.Default != "" {
+			if err := compiled.validateValue(def.Key, def.Default, map[string]bool{}); err != nil {
+				compiled.defaultErr = newValidationError(ReasonInvalidRule, "", def.Key, "rule '%s' default value: %s", def.Key, err.Error())
+			}
+		}
+	}
+
+	return rule
+}
+
+// compileLimits parses the MinValue and MaxValue of the size, duration and float types,
+// and checks that an enum has values to choose from
+func (d *compiledRuleDef) compileLimits() *ValidationError {
+	def := d.def
+
+	switch def.Type {
+	case RuleTypeSize, RuleTypeDuration, RuleTypeFloat:
+	case RuleTypeEnum:
+		if len(def.Enum) == 0 {
+			return newValidationError(ReasonInvalidRule, "", def.Key, "enum rule '%s' has no values", def.Key)
+		}
+		return nil
+	default:
+		return nil
+	}
+
+	var err error
+	if def.MinValue != "" {
+		if d.minValue, err = def.ParseValue(def.MinValue); err != nil {
+			return newValidationError(ReasonInvalidRule, "", def.Key, "rule '%s' minimum value: %s", def.Key, err.Error())
+		}
+	}
+
+	if def.MaxValue != "" {
+		if d.maxValue, err = def.ParseValue(def.MaxValue); err != nil {
+			return newValidationError(ReasonInvalidRule, "", def.Key, "rule '%s' maximum value: %s", def.Key, err.Error())
+		}
+	}
+
+	if d.minValue != nil && d.maxValue != nil && compareValues(d.minValue, d.maxValue) > 0 {
+		return newValidationError(ReasonInvalidRule, "", def.Key, "rule '%s' minimum value '%s' is greater than its maximum value '%s'", def.Key, def.MinValue, def.MaxValue)
+	}
+
+	return nil
+}
+
+// compareValues compares two parsed values of the same type, returning -1, 0 or 1
+func compareValues(a interface{}, b interface{}) int {
+	less, greater := false, false
+	switch a := a.(type) {
+	case int64:
+		less, greater = a < b.(int64), a > b.(int64)
+	case time.Duration:
+		less, greater = a < b.(time.Duration), a > b.(time.Duration)
+	case float64:
+		less, greater = a < b.(float64), a > b.(float64)
+	}
+
+	if less {
+		return -1
+	} else if greater {
+		return 1
+	}
+	return 0
+}
+
+// findRuleDefinition returns the first rule definition whose key matches the argument
+func (r *compiledRule) findRuleDefinition(key string) (*compiledRuleDef, *ValidationError) {
+	for index := range r.defs {
+		def := &r.defs[index]
+		if def.keyErr != nil {
+			return nil, def.keyErr
+		}
+
+		if def.key.MatchString(key) {
+			return def, nil
+		}
+	}
+
+	return nil, newValidationError(ReasonUnsupportedArgument, key, "", "unsupported argument '%s'", key)
+}
+
+// validateArgs validates a map of arguments against the compiled rule, and returns all
+// the problems that were found
+func (r *compiledRule) validateArgs(args map[string]string, uniqueMap map[string]bool) ValidationErrors {
+
+	command, found := args["command"]
+	if !found {
+		return ValidationErrors{newValidationError(ReasonMissingCommand, "", "", "no command in arguments")}
+	}
+
+	if command != r.spec.Command {
+		return ValidationErrors{newValidationError(ReasonInvalidDirective, "", "", "command '%s' does not match rule '%s'", command, r.spec.Command)}
+	}
+
+	// Create a map that maps a directive rule definition to an argument that correctly matches it
+	// key: DWDirectiveRule	value: argument that matches that rule
+	// Required to check that all DWDirectiveRuleDef's have been met
+	argToRuleMap := map[*DWDirectiveRuleDef]string{}
+
+	// Visit the arguments in a fixed order so the problems are always reported in the same order
+	keys := make([]string, 0, len(args))
+	for k := range args {
+		if k != "command" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	errs := ValidationErrors{}
+
+	// Iterate over all arguments and validate each based on the associated rule
+	for _, k := range keys {
+		v := args[k]
+
+		compiled, verr := r.findRuleDefinition(k)
+		if verr != nil {
+			if verr.Reason == ReasonInvalidRule {
+				// Nothing more can be checked with a broken rule
+				return append(errs, verr)
+			}
+			errs = append(errs, verr)
+			continue
+		}
+		rule := compiled.def
+
+		// NOTE: We know that we don't have repeated arguments here because the arguments
+		//       come to us in a map indexed by the argment name.
+		argToRuleMap[rule] = k
+
+		if err := compiled.validateValue(k, v, uniqueMap); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// Iterate over the rules to ensure all required rules have an argument, either from the
+	// directive or from a default
+	for index := range r.defs {
+		compiled := &r.defs[index]
+		rule := compiled.def
+		if _, found := argToRuleMap[rule]; found {
+			continue
+		}
+
+		if rule.Default != "" {
+			// A default takes part in the uniqueness check like any other value, so at
+			// most one directive can use the default of a unique argument
+			if compiled.defaultErr != nil {
+				errs = append(errs, compiled.defaultErr)
+			} else if err := compiled.checkUnique(rule.Key, rule.Default, uniqueMap); err != nil {
+				errs = append(errs, err)
+			}
+		} else if rule.IsRequired {
+			errs = append(errs, newValidationError(ReasonMissingArgument, "", rule.Key, "missing required argument '%v'", rule.Key))
+		}
+	}
+
+	return append(errs, r.validateConstraints(args)...)
+}
+
+// defaults returns the default value of each rule definition whose argument isn't in args
+func (r *compiledRule) defaults(args map[string]string) map[string]string {
+	defaults := map[string]string{}
+	for _, rule := range r.spec.RuleDefs {
+		if rule.Default == "" {
+			continue
+		}
+
+		if _, found := args[rule.Key]; !found {
+			defaults[rule.Key] = rule.Default
+		}
+	}
+
+	return defaults
+}
+
+// validateConstraints checks the arguments against each of the rule's constraints
+func (r *compiledRule) validateConstraints(args map[string]string) ValidationErrors {
+	if len(r.spec.Constraints) == 0 {
+		return nil
+	}
+
+	// Conditional constraints see the defaults as well as the directive's own arguments
+	effective := r.defaults(args)
+	for k, v := range args {
+		effective[k] = v
+	}
+
+	errs := ValidationErrors{}
+	for _, constraint := range r.spec.Constraints {
+		present := []string{}
+		missing := []string{}
+		for _, key := range constraint.Keys {
+			if _, found := args[key]; found {
+				present = append(present, key)
+			} else {
+				missing = append(missing, key)
+			}
+		}
+
+		switch constraint.Type {
+		case ConstraintMutuallyExclusive:
+			if len(present) > 1 {
+				errs = append(errs, newValidationError(ReasonConstraint, present[1], "", "arguments '%s' are mutually exclusive", strings.Join(present, "', '")))
+			}
+		case ConstraintCoRequired:
+			if len(present) > 0 && len(missing) > 0 {
+				errs = append(errs, newValidationError(ReasonConstraint, present[0], "", "argument '%s' requires '%s'", present[0], strings.Join(missing, "', '")))
+			}
+		case ConstraintConditional:
+			if constraint.If == nil || constraint.Then == nil {
+				errs = append(errs, newValidationError(ReasonInvalidRule, "", "", "conditional constraint for command '%s' needs both an if and a then condition", r.spec.Command))
+				continue
+			}
+
+			if r.conditionMatches(constraint.If, effective) && !r.conditionMatches(constraint.Then, effective) {
+				errs = append(errs, newValidationError(ReasonConstraint, constraint.If.Key, "", "argument %s is only valid with %s", describeCondition(constraint.If), describeCondition(constraint.Then)))
+			}
+		default:
+			errs = append(errs, newValidationError(ReasonInvalidRule, "", "", "unsupported constraint type '%s'", constraint.Type))
+		}
+	}
+
+	return errs
+}
+
+// conditionMatches returns true if the arguments meet the condition
+func (r *compiledRule) conditionMatches(condition *DWDirectiveArgCondition, args map[string]string) bool {
+	value, found := args[condition.Key]
+	if !found {
+		return false
+	}
+
+	if condition.Value == "" {
+		return true
+	}
+
+	if value == emptyValue {
+		// Only a valueless bool can be compared with a value
+		value = "true"
+	}
+
+	if value == condition.Value {
+		return true
+	}
+
+	if compiled, err := r.findRuleDefinition(condition.Key); err == nil && compiled.def.Type == RuleTypeListOfString {
+		return slices.Contains(strings.Split(value, ","), condition.Value)
+	}
+
+	return false
+}
+
+// describeCondition writes a condition the way it would appear in a directive
+func describeCondition(condition *DWDirectiveArgCondition) string {
+	if condition.Value == "" {
+		return fmt.Sprintf("'%s'", condition.Key)
+	}
+
+	return fmt.Sprintf("'%s=%s'", condition.Key, condition.Value)
+}
+
+// validateValue validates the value of a single argument against its rule definition
+func (d *compiledRuleDef) validateValue(k string, v string, uniqueMap map[string]bool) *ValidationError {
+	rule := d.def
+
+	invalid := func(reason ValidationReason, format string, a ...interface{}) *ValidationError {
+		return newValidationError(reason, k, rule.Key, format, a...)
+	}
+
+	if v == emptyValue {
+		switch {
+		case rule.IsValueRequired:
+			return invalid(ReasonValueRequired, "argument '%s' requires a value", k)
+		case rule.Type == RuleTypeBool:
+			// Booleans default to true.
+			v = "true"
+		case rule.Type == RuleTypeString || rule.Type == RuleTypeListOfString:
+			v = ""
+		default:
+			// The other types have no meaning without a value
+			return invalid(ReasonValueRequired, "argument '%s' requires a value", k)
+		}
+	}
+
+	switch rule.Type {
+	case RuleTypeInteger:
+		i, err := strconv.Atoi(v)
+		if err != nil {
+			return invalid(ReasonInvalidValue, "argument '%s' invalid integer '%s'", k, v)
+		}
+		if rule.Max != 0 && i > rule.Max {
+			return invalid(ReasonOutOfRange, "argument '%s' specified integer value %d is greather than maximum value %d", k, i, rule.Max)
+		}
+		if rule.Min != 0 && i < rule.Min {
+			return invalid(ReasonOutOfRange, "argument '%s' specified integer value %d is less than minimum value %d", k, i, rule.Min)
+		}
+	case RuleTypeBool:
+		// Check the value with the same parser that ParseValue uses. A pattern further
+		// limits the value to true or false.
+		if _, err := rule.ParseValue(v); err != nil {
+			return invalid(ReasonInvalidValue, "argument '%s' invalid boolean '%s'", k, v)
+		}
+		if rule.Pattern != "" {
+			if !boolMatcher.MatchString(v) {
+				return invalid(ReasonInvalidValue, "argument '%s' invalid boolean '%s'", k, v)
+			}
+		}
+	case RuleTypeString:
+		if rule.Pattern != "" {
+			if d.patternErr != nil {
+				return d.patternErr
+			}
+
+			if !d.pattern.MatchString(v) {
+				return invalid(ReasonInvalidValue, "argument '%s' invalid string '%s'", k, v)
+			}
+		}
+	case RuleTypeListOfString:
+		words := strings.Split(v, ",")
+		if len(rule.Patterns) > 0 {
+			if d.patternsErr != nil {
+				return d.patternsErr
+			}
+
+			wordsMatched := make(map[string]bool)
+			for _, word := range words {
+				for _, re := range d.patterns {
+					if re.MatchString(word) {
+						wordsMatched[word] = true
+						break
+					}
+				}
+				if !wordsMatched[word] {
+					return invalid(ReasonInvalidValue, "argument '%s' invalid string '%s' in list '%s'", k, word, v)
+				}
+			}
+		}
+		// All of the words in the list are valid, but were any words repeated?
+		wordMap := make(map[string]bool)
+		for _, word := range words {
+			if _, present := wordMap[word]; present {
+				return invalid(ReasonRepeatedWord, "argument '%s' word '%s' repeated in list '%s'", k, word, v)
+			}
+			wordMap[word] = true
+		}
+	case RuleTypeSize, RuleTypeDuration, RuleTypeFloat:
+		if d.limitsErr != nil {
+			return d.limitsErr
+		}
+
+		parsed, err := rule.ParseValue(v)
+		if err != nil {
+			return invalid(ReasonInvalidValue, "argument '%s' %s", k, err.Error())
+		}
+		if d.minValue != nil && compareValues(parsed, d.minValue) < 0 {
+			return invalid(ReasonOutOfRange, "argument '%s' specified %s value '%s' is less than minimum value '%s'", k, rule.Type, v, rule.MinValue)
+		}
+		if d.maxValue != nil && compareValues(parsed, d.maxValue) > 0 {
+			return invalid(ReasonOutOfRange, "argument '%s' specified %s value '%s' is greater than maximum value '%s'", k, rule.Type, v, rule.MaxValue)
+		}
+	case RuleTypeEnum:
+		if d.limitsErr != nil {
+			return d.limitsErr
+		}
+
+		if !slices.Contains(rule.Enum, v) {
+			return invalid(ReasonInvalidValue, "argument '%s' invalid value '%s', expected one of '%s'", k, v, strings.Join(rule.Enum, ", "))
+		}
+	default:
+		return invalid(ReasonInvalidRule, "unsupported rule type '%s'", rule.Type)
+	}
+
+	return d.checkUnique(k, v, uniqueMap)
+}
+
+// checkUnique checks that no other argument in the uniqueMap has the value v when the rule
+// definition requires its values to be unique, and then records v in the uniqueMap
+func (d *compiledRuleDef) checkUnique(k string, v string, uniqueMap map[string]bool) *ValidationError {
+	rule := d.def
+	if rule.UniqueWithin == "" {
+		return nil
+	}
+
+	if _, ok := uniqueMap[rule.UniqueWithin+"/"+v]; ok {
+		return newValidationError(ReasonNotUnique, k, rule.Key, "value '%s' must be unique within '%s'", v, rule.UniqueWithin)
+	}
+
+	uniqueMap[rule.UniqueWithin+"/"+v] = true
+
+	return nil
+}
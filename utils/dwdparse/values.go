@@ -0,0 +1,159 @@
+/*
+ * Copyright 2025 Hewlett Packard Enterprise Development LP
+ * Other additional copyright holders may be indicated within.
+ *
+ * The entirety of this work is licensed under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ *
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dwdparse
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sizeUnits maps the lower-cased unit suffixes of a size to their multipliers. SI units
+// are powers of 1000 and IEC units are powers of 1024.
+var sizeUnits = map[string]int64{
+	"":    1,
+	"b":   1,
+	"k":   1000,
+	"kb":  1000,
+	"m":   1000 * 1000,
+	"mb":  1000 * 1000,
+	"g":   1000 * 1000 * 1000,
+	"gb":  1000 * 1000 * 1000,
+	"t":   1000 * 1000 * 1000 * 1000,
+	"tb":  1000 * 1000 * 1000 * 1000,
+	"p":   1000 * 1000 * 1000 * 1000 * 1000,
+	"pb":  1000 * 1000 * 1000 * 1000 * 1000,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+	"pib": 1 << 50,
+}
+
+var sizeMatcher = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)?)\s*([A-Za-z]*)$`)
+
+// ParseSize parses a size with an optional SI (KB, MB, GB, TB, PB) or IEC (KiB, MiB, GiB,
+// TiB, PiB) unit and returns the number of bytes. The units are not case sensitive, and
+// a size without a unit is in bytes. For example, "10TiB", "1.5GB" and "4096".
+func ParseSize(value string) (int64, error) {
+	match := sizeMatcher.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return 0, fmt.Errorf("invalid size '%s'", value)
+	}
+
+	multiplier, found := sizeUnits[strings.ToLower(match[2])]
+	if !found {
+		return 0, fmt.Errorf("invalid size unit '%s' in '%s'", match[2], value)
+	}
+
+	// Whole numbers are multiplied exactly. Fractions are rounded to the nearest byte.
+	if number, err := strconv.ParseInt(match[1], 10, 64); err == nil {
+		if number > math.MaxInt64/multiplier {
+			return 0, fmt.Errorf("size '%s' is too large", value)
+		}
+		return number * multiplier, nil
+	}
+
+	number, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size '%s'", value)
+	}
+
+	bytes := math.Round(number * float64(multiplier))
+	if bytes >= math.MaxInt64 {
+		return 0, fmt.Errorf("size '%s' is too large", value)
+	}
+
+	return int64(bytes), nil
+}
+
+// ParseDuration parses a duration such as "90s", "1h30m" or "250ms". Negative durations
+// are not allowed.
+func ParseDuration(value string) (time.Duration, error) {
+	duration, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration '%s'", value)
+	}
+
+	if duration < 0 {
+		return 0, fmt.Errorf("negative duration '%s'", value)
+	}
+
+	return duration, nil
+}
+
+// ParseFloat parses a finite floating point number
+func ParseFloat(value string) (float64, error) {
+	number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || math.IsInf(number, 0) || math.IsNaN(number) {
+		return 0, fmt.Errorf("invalid float '%s'", value)
+	}
+
+	return number, nil
+}
+
+// ParseValue parses an argument's value according to the type of the rule definition
+// and returns it in its normalized form:
+//
+//	integer        int
+//	bool           bool
+//	string         string
+//	list-of-string []string
+//	size           int64, in bytes
+//	duration       time.Duration
+//	float          float64
+//	enum           string
+//
+// Only the value's syntax is checked; use Validate to check it against the rule's
+// patterns and limits.
+func (def *DWDirectiveRuleDef) ParseValue(value string) (interface{}, error) {
+	switch def.Type {
+	case RuleTypeInteger:
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer '%s'", value)
+		}
+		return i, nil
+	case RuleTypeBool:
+		if value == "" || value == emptyValue {
+			return true, nil
+		}
+		b, err := strconv.ParseBool(strings.ToLower(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid boolean '%s'", value)
+		}
+		return b, nil
+	case RuleTypeString, RuleTypeEnum:
+		return value, nil
+	case RuleTypeListOfString:
+		return strings.Split(value, ","), nil
+	case RuleTypeSize:
+		return ParseSize(value)
+	case RuleTypeDuration:
+		return ParseDuration(value)
+	case RuleTypeFloat:
+		return ParseFloat(value)
+	default:
+		return nil, fmt.Errorf("unsupported rule type '%s'", def.Type)
+	}
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright 2025 Hewlett Packard Enterprise Development LP
+ * Other additional copyright holders may be indicated within.
+ *
+ * The entirety of this work is licensed under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ *
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dwdparse
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		value string
+		bytes int64
+		fail  bool
+	}{
+		{value: "4096", bytes: 4096},
+		{value: "10B", bytes: 10},
+		{value: "10KB", bytes: 10 * 1000},
+		{value: "10KiB", bytes: 10 * 1024},
+		{value: "10gib", bytes: 10 << 30},
+		{value: "1.5GB", bytes: 1500 * 1000 * 1000},
+		{value: "10TiB", bytes: 10 << 40},
+		{value: "2PB", bytes: 2 * 1000 * 1000 * 1000 * 1000 * 1000},
+		{value: "10 GiB", bytes: 10 << 30},
+		{value: "10XB", fail: true},
+		{value: "-10GiB", fail: true},
+		{value: "GiB", fail: true},
+		{value: "100000PiB", fail: true},
+	}
+
+	for _, tt := range tests {
+		bytes, err := ParseSize(tt.value)
+		if tt.fail {
+			if err == nil {
+				t.Errorf("ParseSize(%s): expected an error, got %d", tt.value, bytes)
+			}
+		} else if err != nil || bytes != tt.bytes {
+			t.Errorf("ParseSize(%s): expected %d, got %d, err(%v)", tt.value, tt.bytes, bytes, err)
+		}
+	}
+}
+
+func TestParseValue(t *testing.T) {
+	tests := []struct {
+		ruleType string
+		value    string
+		expected interface{}
+	}{
+		{ruleType: RuleTypeInteger, value: "42", expected: 42},
+		{ruleType: RuleTypeBool, value: "True", expected: true},
+		{ruleType: RuleTypeString, value: "abc", expected: "abc"},
+		{ruleType: RuleTypeListOfString, value: "a,b", expected: []string{"a", "b"}},
+		{ruleType: RuleTypeSize, value: "1MiB", expected: int64(1 << 20)},
+		{ruleType: RuleTypeDuration, value: "1h30m", expected: 90 * time.Minute},
+		{ruleType: RuleTypeFloat, value: "0.25", expected: 0.25},
+		{ruleType: RuleTypeEnum, value: "fast", expected: "fast"},
+	}
+
+	for _, tt := range tests {
+		def := DWDirectiveRuleDef{Key: "key", Type: tt.ruleType}
+		value, err := def.ParseValue(tt.value)
+		if err != nil || !reflect.DeepEqual(value, tt.expected) {
+			t.Errorf("ParseValue(%s, %s): expected %v, got %v, err(%v)", tt.ruleType, tt.value, tt.expected, value, err)
+		}
+	}
+}
+
+func TestRichRuleTypes(t *testing.T) {
+	rules := []DWDirectiveRuleSpec{{
+		Command: "rich",
+		RuleDefs: []DWDirectiveRuleDef{
+			{Key: "capacity", Type: RuleTypeSize, MinValue: "1GiB", MaxValue: "10TiB"},
+			{Key: "timeout", Type: RuleTypeDuration, MaxValue: "1h"},
+			{Key: "ratio", Type: RuleTypeFloat, MinValue: "0", MaxValue: "1"},
+			{Key: "mode", Type: RuleTypeEnum, Enum: []string{"fast", "safe"}},
+		},
+	}}
+
+	tests := []testCase{
+		{directives: []string{"#DW rich capacity=10TiB timeout=30m ratio=0.5 mode=fast"}, result: pass},
+		{directives: []string{"#DW rich capacity=1GB"}, result: fail},
+		{directives: []string{"#DW rich capacity=11TiB"}, result: fail},
+		{directives: []string{"#DW rich capacity=lots"}, result: fail},
+		{directives: []string{"#DW rich timeout=2h"}, result: fail},
+		{directives: []string{"#DW rich timeout=soon"}, result: fail},
+		{directives: []string{"#DW rich ratio=1.5"}, result: fail},
+		{directives: []string{"#DW rich mode=slow"}, result: fail},
+	}
+
+	test(t, rules, tests)
+
+	badRules := []DWDirectiveRuleSpec{{
+		Command: "bad",
+		RuleDefs: []DWDirectiveRuleDef{
+			{Key: "capacity", Type: RuleTypeSize, MinValue: "10GiB", MaxValue: "1GiB"},
+			{Key: "mode", Type: RuleTypeEnum},
+		},
+	}}
+
+	test(t, badRules, []testCase{
+		{directives: []string{"#DW bad capacity=5GiB"}, result: fail},
+		{directives: []string{"#DW bad mode=fast"}, result: fail},
+	})
+}
@@ -25,6 +25,51 @@ package dwdparse
 
 import ()
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DWDirectiveArgCondition) DeepCopyInto(out *DWDirectiveArgCondition) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DWDirectiveArgCondition.
+func (in *DWDirectiveArgCondition) DeepCopy() *DWDirectiveArgCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(DWDirectiveArgCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DWDirectiveRuleConstraint) DeepCopyInto(out *DWDirectiveRuleConstraint) {
+	*out = *in
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.If != nil {
+		in, out := &in.If, &out.If
+		*out = new(DWDirectiveArgCondition)
+		**out = **in
+	}
+	if in.Then != nil {
+		in, out := &in.Then, &out.Then
+		*out = new(DWDirectiveArgCondition)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DWDirectiveRuleConstraint.
+func (in *DWDirectiveRuleConstraint) DeepCopy() *DWDirectiveRuleConstraint {
+	if in == nil {
+		return nil
+	}
+	out := new(DWDirectiveRuleConstraint)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DWDirectiveRuleDef) DeepCopyInto(out *DWDirectiveRuleDef) {
 	*out = *in
@@ -33,6 +78,11 @@ func (in *DWDirectiveRuleDef) DeepCopyInto(out *DWDirectiveRuleDef) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Enum != nil {
+		in, out := &in.Enum, &out.Enum
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DWDirectiveRuleDef.
@@ -48,6 +98,11 @@ func (in *DWDirectiveRuleDef) DeepCopy() *DWDirectiveRuleDef {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DWDirectiveRuleSpec) DeepCopyInto(out *DWDirectiveRuleSpec) {
 	*out = *in
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.RuleDefs != nil {
 		in, out := &in.RuleDefs, &out.RuleDefs
 		*out = make([]DWDirectiveRuleDef, len(*in))
@@ -55,6 +110,13 @@ func (in *DWDirectiveRuleSpec) DeepCopyInto(out *DWDirectiveRuleSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Constraints != nil {
+		in, out := &in.Constraints, &out.Constraints
+		*out = make([]DWDirectiveRuleConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DWDirectiveRuleSpec.
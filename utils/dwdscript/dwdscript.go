@@ -0,0 +1,186 @@
+/*
+ * Copyright 2025 Hewlett Packard Enterprise Development LP
+ * Other additional copyright holders may be indicated within.
+ *
+ * The entirety of this work is licensed under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ *
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package dwdscript finds the #DW directives in a batch job script so a WLM can
+// put them in a Workflow's spec.dwDirectives.
+package dwdscript
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// DefaultPrefix is the prefix of a data workflow directive, and the prefix that
+// dwdparse expects
+const DefaultPrefix = "#DW"
+
+// maxLineLength is the longest script line that can be read
+const maxLineLength = 1024 * 1024
+
+// Options control how directives are found in a script
+type Options struct {
+	// Prefixes that start a directive line, such as "#DW" or "#BB". A prefix must be
+	// at the start of a line, ignoring leading whitespace, and be followed by whitespace.
+	// Defaults to DefaultPrefix.
+	Prefixes []string
+
+	// KeepPrefix leaves the prefix of each directive as it was in the script. Otherwise
+	// the prefix is replaced with DefaultPrefix so the directive can be given to dwdparse.
+	KeepPrefix bool
+
+	// StopAtCommand stops looking for directives at the first line that isn't blank and
+	// isn't a comment, the way batch systems stop reading their own directives
+	StopAtCommand bool
+}
+
+// Directive is a directive found in a job script
+type Directive struct {
+	// Text is the directive with any continuation lines joined by a single space. Whitespace
+	// within a line is left alone, since it may be part of a quoted value.
+	Text string
+
+	// Prefix is the prefix the directive had in the script
+	Prefix string
+
+	// Line is the 1-based line number of the first line of the directive
+	Line int
+
+	// EndLine is the line number of the last line of the directive, which is different
+	// from Line when the directive is continued
+	EndLine int
+}
+
+// Extract reads a job script and returns its directives in the order they appear.
+//
+// A line that ends with a backslash is continued on the next line. The continuation line
+// may repeat the directive's prefix, which is dropped. For example:
+//
+//	#DW jobdw type=xfs \
+//	#DW       capacity=1TiB name=scratch
+//
+// is the single directive "#DW jobdw type=xfs capacity=1TiB name=scratch".
+func Extract(r io.Reader, opts Options) ([]Directive, error) {
+	prefixes := opts.Prefixes
+	if len(prefixes) == 0 {
+		prefixes = []string{DefaultPrefix}
+	}
+
+	directives := []Directive{}
+	var current *Directive
+	var parts []string
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineLength)
+
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+
+		if current == nil {
+			if lineNumber == 1 && strings.HasPrefix(line, "#!") {
+				continue
+			}
+
+			prefix := matchPrefix(line, prefixes)
+			if prefix == "" {
+				if opts.StopAtCommand && line != "" && !strings.HasPrefix(line, "#") {
+					break
+				}
+				continue
+			}
+
+			current = &Directive{Prefix: prefix, Line: lineNumber}
+			parts = []string{}
+			line = line[len(prefix):]
+		} else if strings.HasPrefix(line, current.Prefix) && startsWord(line[len(current.Prefix):]) {
+			line = line[len(current.Prefix):]
+		}
+
+		continued := continues(line)
+		if continued {
+			line = line[:len(line)-1]
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			parts = append(parts, line)
+		}
+
+		if !continued {
+			current.EndLine = lineNumber
+			current.Text = directiveText(current.Prefix, parts, opts.KeepPrefix)
+			directives = append(directives, *current)
+			current = nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("line %d: %w", lineNumber+1, err)
+	}
+
+	if current != nil {
+		return nil, fmt.Errorf("line %d: directive continues past the end of the script", current.Line)
+	}
+
+	return directives, nil
+}
+
+// Texts returns the text of each directive, ready for a Workflow's spec.dwDirectives
+func Texts(directives []Directive) []string {
+	texts := make([]string, 0, len(directives))
+	for _, directive := range directives {
+		texts = append(texts, directive.Text)
+	}
+
+	return texts
+}
+
+// matchPrefix returns the prefix that starts the line, or "" if no prefix does
+func matchPrefix(line string, prefixes []string) string {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(line, prefix) && startsWord(line[len(prefix):]) {
+			return prefix
+		}
+	}
+
+	return ""
+}
+
+// startsWord returns true if the rest of a line after a prefix is empty or starts with
+// whitespace, so that "#DWX" isn't taken for "#DW"
+func startsWord(rest string) bool {
+	return rest == "" || unicode.IsSpace(rune(rest[0]))
+}
+
+// continues returns true if the line ends with a backslash that isn't itself escaped
+func continues(line string) bool {
+	backslashes := len(line) - len(strings.TrimRight(line, `\`))
+	return backslashes%2 == 1
+}
+
+// directiveText joins the lines of a directive behind its prefix
+func directiveText(prefix string, lines []string, keepPrefix bool) string {
+	if !keepPrefix {
+		prefix = DefaultPrefix
+	}
+
+	return strings.Join(append([]string{prefix}, lines...), " ")
+}
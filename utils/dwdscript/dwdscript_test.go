@@ -0,0 +1,107 @@
+/*
+ * Copyright 2025 Hewlett Packard Enterprise Development LP
+ * Other additional copyright holders may be indicated within.
+ *
+ * The entirety of this work is licensed under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ *
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dwdscript
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+)
+
+const script = `#!/bin/bash
+#SBATCH -N 4
+#DW jobdw type=xfs capacity=1TiB name=scratch
+  #DW copy_in source="/lus/my  dir" \
+  #DW     destination=$DW_JOB_scratch
+##DW jobdw type=lustre capacity=1TiB name=disabled
+#DWX not a directive
+#BB create_persistent name=pers \
+        capacity=10GiB
+
+srun ./a.out
+#DW jobdw type=xfs capacity=1GiB name=late
+`
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		opts       Options
+		directives []Directive
+	}{
+		{
+			opts: Options{},
+			directives: []Directive{
+				{Text: "#DW jobdw type=xfs capacity=1TiB name=scratch", Prefix: "#DW", Line: 3, EndLine: 3},
+				{Text: `#DW copy_in source="/lus/my  dir" destination=$DW_JOB_scratch`, Prefix: "#DW", Line: 4, EndLine: 5},
+				{Text: "#DW jobdw type=xfs capacity=1GiB name=late", Prefix: "#DW", Line: 12, EndLine: 12},
+			},
+		},
+		{
+			opts: Options{Prefixes: []string{"#DW", "#BB"}, StopAtCommand: true},
+			directives: []Directive{
+				{Text: "#DW jobdw type=xfs capacity=1TiB name=scratch", Prefix: "#DW", Line: 3, EndLine: 3},
+				{Text: `#DW copy_in source="/lus/my  dir" destination=$DW_JOB_scratch`, Prefix: "#DW", Line: 4, EndLine: 5},
+				{Text: "#DW create_persistent name=pers capacity=10GiB", Prefix: "#BB", Line: 8, EndLine: 9},
+			},
+		},
+		{
+			opts: Options{Prefixes: []string{"#BB"}, KeepPrefix: true},
+			directives: []Directive{
+				{Text: "#BB create_persistent name=pers capacity=10GiB", Prefix: "#BB", Line: 8, EndLine: 9},
+			},
+		},
+	}
+
+	for index, tt := range tests {
+		directives, err := Extract(strings.NewReader(script), tt.opts)
+		if err != nil {
+			t.Errorf("TestExtract(%d): unexpected error: %v", index, err)
+		} else if !reflect.DeepEqual(directives, tt.directives) {
+			t.Errorf("TestExtract(%d): expected %+v, got %+v", index, tt.directives, directives)
+		}
+	}
+}
+
+func TestExtractUnfinishedContinuation(t *testing.T) {
+	_, err := Extract(strings.NewReader("#DW jobdw type=xfs \\\n"), Options{})
+	if err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("TestExtractUnfinishedContinuation: expected an error for line 1, got %v", err)
+	}
+}
+
+func TestTexts(t *testing.T) {
+	directives, err := Extract(strings.NewReader(script), Options{StopAtCommand: true})
+	if err != nil {
+		t.Fatalf("TestTexts: unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"#DW jobdw type=xfs capacity=1TiB name=scratch",
+		`#DW copy_in source="/lus/my  dir" destination=$DW_JOB_scratch`,
+	}
+	if texts := Texts(directives); !reflect.DeepEqual(texts, expected) {
+		t.Errorf("TestTexts: expected %v, got %v", expected, texts)
+	}
+}
+
+// Just touch ginkgo, so it's here to interpret any ginkgo args from
+// "make test", so that doesn't fail on this test file.
+var _ = BeforeSuite(func() {})